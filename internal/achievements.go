@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/zrcoder/rdor/internal/ballsort"
+	"github.com/zrcoder/rdor/internal/hanoi"
+	"github.com/zrcoder/rdor/internal/last"
+	"github.com/zrcoder/rdor/internal/maze"
+	"github.com/zrcoder/rdor/internal/sokoban"
+	"github.com/zrcoder/rdor/pkg/achievement"
+	"github.com/zrcoder/rdor/pkg/game"
+)
+
+// achievements returns all the achievements across the games,
+// they are created for every tracker since some remember the level being played
+func achievements() []*achievement.Achievement {
+	var (
+		hardLevels = last.HardLevels()
+		// undone tells whether the current level of sokoban is played with undos
+		undone bool
+		// failed are the levels failed and not succeeded yet, by the game and the level
+		failed = map[string]bool{}
+	)
+	levelMark := func(e game.Event) string { return strconv.Itoa(e.Level) }
+	return []*achievement.Achievement{
+		{
+			ID: "first-success", Name: "First steps", Goal: 1,
+			Desc: "succeed in any level",
+			Mark: func(e game.Event) string {
+				if e.Kind == game.Succeeded {
+					return "done"
+				}
+				return ""
+			},
+		},
+		{
+			ID: "hanoi-7-optimal", Name: "Monk of Hanoi", Goal: 1,
+			Desc: "solve Hanoi of 7 disks in the fewest moves",
+			Mark: func(e game.Event) string {
+				if e.Kind == game.Succeeded && e.Game == hanoi.Name && e.Level == hanoi.Level(7) && e.Stars == e.TotalStars {
+					return levelMark(e)
+				}
+				return ""
+			},
+		},
+		{
+			ID: "sokoban-no-undo", Name: "No regrets", Goal: 10,
+			Desc: "complete 10 Sokoban levels without undo",
+			Mark: func(e game.Event) string {
+				if e.Game != sokoban.Name {
+					return ""
+				}
+				switch e.Kind {
+				case game.LevelStarted:
+					undone = false
+				case game.Undone:
+					undone = true
+				case game.Succeeded:
+					if !undone {
+						return levelMark(e)
+					}
+				}
+				return ""
+			},
+		},
+		{
+			ID: "last-hard", Name: "The last one", Goal: len(hardLevels),
+			Desc: "win Last on every hard level",
+			Mark: func(e game.Event) string {
+				if e.Kind == game.Succeeded && e.Game == last.Name && slices.Contains(hardLevels, e.Level) {
+					return levelMark(e)
+				}
+				return ""
+			},
+		},
+		{
+			ID: "maze-5", Name: "Pathfinder", Goal: 5,
+			Desc: "escape 5 different mazes",
+			Mark: func(e game.Event) string {
+				if e.Kind == game.Succeeded && e.Game == maze.Name {
+					return levelMark(e)
+				}
+				return ""
+			},
+		},
+		{
+			ID: "ballsort-par", Name: "Tidy", Goal: 1,
+			Desc: "sort the balls within the par",
+			Mark: func(e game.Event) string {
+				if e.Kind == game.Succeeded && e.Game == ballsort.Name && e.TotalStars > 0 && e.Stars == e.TotalStars {
+					return levelMark(e)
+				}
+				return ""
+			},
+		},
+		{
+			ID: "comeback", Name: "Comeback", Goal: 1,
+			Desc: "succeed in a level failed before",
+			Mark: func(e game.Event) string {
+				k := fmt.Sprintf("%s/%d", e.Game, e.Level)
+				switch e.Kind {
+				case game.Failed:
+					failed[k] = true
+				case game.Succeeded:
+					if failed[k] {
+						return k
+					}
+				}
+				return ""
+			},
+		},
+	}
+}
@@ -1,46 +1,70 @@
 package ballsort
 
 import (
+	"errors"
+	"fmt"
 	"math/rand"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	lg "github.com/charmbracelet/lipgloss"
+	engine "github.com/zrcoder/rdor/pkg/engine/ballsort"
 	"github.com/zrcoder/rdor/pkg/game"
+	"github.com/zrcoder/rdor/pkg/style"
 	"github.com/zrcoder/rdor/pkg/style/color"
 )
 
-var (
-	ballStyles    []lg.Style
-	fullTubeNames = []string{"A", "B", "C", "D", "E", "F", "G", "H", "I"}
-)
+var fullTubeNames = []string{"A", "B", "C", "D", "E", "F", "G", "H", "I"}
 
 const (
-	name          = "Ball Sort"
-	tubeCap       = 4
-	defaultColors = 5
-	emptyTubes    = 2
-	levels        = 3
+	// Name is the name of the game, which the events and the records tell the game by
+	Name       = "Ball Sort"
+	autoDelay  = 300 * time.Millisecond
+	totalStars = 3
 )
 
+var errNoSolution = errors.New("no solution from here, reset please")
+
 func New() game.Game {
-	return &ballSort{Base: game.New(name)}
+	return &ballSort{Base: game.New(Name)}
+}
+
+// NewDaily returns the game of one board, generated with the seed given to UseSeed,
+// without the hint and the solver
+func NewDaily() game.Game {
+	return &ballSort{Base: game.New(Name), daily: true}
 }
 
 type ballSort struct {
 	*game.Base
 	rd        *rand.Rand
 	buf       *strings.Builder
-	tubes     map[string]*Tube
-	overBall  *Ball
-	balls     []*Ball
+	hintKey   *key.Binding
+	autoKey   *key.Binding
+	engine    *engine.Game
+	hint      *engine.Move
 	tubeNames []string
+	autoMoves []engine.Move
 	colors    int
+	capacity  int
+	level     int
+	picked    int // the tube picked up the top balls, -1 if none
+	ticker    int
+	par       int
+	autoUsed  bool
+	daily     bool
+	// palette are the styles of the colors, shuffled into ballStyles for every level
+	palette    []lg.Style
+	ballStyles []lg.Style
 }
 
+type tickMsg struct{ ticker int }
+
 func (p *ballSort) Init() tea.Cmd {
-	ballStyles = []lg.Style{
+	p.palette = []lg.Style{
 		lg.NewStyle().Foreground(color.Red),
 		lg.NewStyle().Foreground(color.Orange),
 		lg.NewStyle().Foreground(color.Yellow),
@@ -50,12 +74,32 @@ func (p *ballSort) Init() tea.Cmd {
 		lg.NewStyle(), // black/white as default
 	}
 	p.RegisterView(p.view)
-	p.RegisterLevels(levels, p.set)
-	p.DisabledPrevKey()
-	p.DisabledSetKey()
+	if p.daily {
+		p.RegisterLevels(1, p.set)
+		p.DisabledNextKey()
+		p.DisabledPrevKey()
+		p.DisabledSetKey()
+	} else {
+		p.RegisterLevels(totalLevels, p.set)
+	}
+	p.RegisterMoves(p.Moves)
 	p.buf = &strings.Builder{}
-
-	p.rd = rand.New(rand.NewSource(time.Now().UTC().UnixNano()))
+	hintKey := key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "hint"),
+	)
+	p.hintKey = &hintKey
+	autoKey := key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "auto solve"),
+	)
+	p.autoKey = &autoKey
+	p.ClearGroups()
+	if p.daily {
+		p.autoKey.SetEnabled(false)
+	} else {
+		p.AddKeyGroup(game.KeyGroup{p.hintKey, p.autoKey})
+	}
 	p.set(0)
 	return p.Base.Init()
 }
@@ -67,10 +111,24 @@ func (p *ballSort) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	switch msg := msg.(type) {
+	case tickMsg:
+		if msg.ticker == p.ticker {
+			return p, p.autoMove()
+		}
 	case tea.KeyMsg:
-		key := strings.ToUpper(msg.String())
-		if tube, ok := p.tubes[key]; ok {
-			p.pick(tube)
+		p.hint = nil
+		switch {
+		case key.Matches(msg, *p.hintKey):
+			p.showHint()
+			return p, cmd
+		case key.Matches(msg, *p.autoKey):
+			return p, tea.Batch(cmd, p.autoSolve())
+		}
+		if len(p.autoMoves) > 0 {
+			return p, cmd
+		}
+		if i := slices.Index(p.tubeNames, strings.ToUpper(msg.String())); i != -1 {
+			p.pick(i)
 		}
 	}
 	return p, cmd
@@ -78,81 +136,153 @@ func (p *ballSort) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (p *ballSort) view() string {
 	views := make([]string, 0, len(p.tubeNames))
-	for _, name := range p.tubeNames {
+	for i, name := range p.tubeNames {
 		views = append(views, lg.JoinVertical(lg.Center,
-			p.tubes[name].view(),
+			p.tubeView(i),
 			name,
 		), " ")
 	}
+	state := fmt.Sprintf("level %d/%d  moves: %d  par: %d", p.level+1, totalLevels, p.engine.Steps(), p.par)
+	if p.daily {
+		state = fmt.Sprintf("daily  moves: %d  par: %d", p.engine.Steps(), p.par)
+	}
+	if p.hint != nil {
+		state += "  hint: " + p.tubeNames[p.hint.From] + " → " + p.tubeNames[p.hint.To]
+	}
 	return lg.JoinVertical(lg.Center,
 		lg.JoinHorizontal(lg.Top, views...),
+		"",
+		style.Help.Render(state),
 	)
 }
 
 func (p *ballSort) set(levle int) {
-	p.colors = defaultColors + levle
-	p.tubeNames = fullTubeNames[:p.colors+emptyTubes]
-	p.rd.Shuffle(len(ballStyles), func(i, j int) {
-		ballStyles[i], ballStyles[j] = ballStyles[j], ballStyles[i]
-	})
-	p.tubes = make(map[string]*Tube, len(p.tubeNames))
-	for _, name := range p.tubeNames {
-		p.tubes[name] = NewTube(tubeCap)
-	}
-	p.balls = make([]*Ball, 0, p.colors*tubeCap)
-	for i := 0; i < p.colors; i++ {
-		for j := 0; j < tubeCap; j++ {
-			p.balls = append(p.balls, &Ball{id: i})
-		}
+	lvl := getLevel(levle)
+	if p.daily {
+		lvl = getLevel(dailyLevel)
+		lvl.seed = p.LevelSeed()
 	}
-	p.rd.Shuffle(len(p.balls), func(i, j int) {
-		p.balls[i], p.balls[j] = p.balls[j], p.balls[i]
+	p.level = levle
+	p.rd = rand.New(rand.NewSource(lvl.seed))
+	p.colors = lvl.colors
+	p.capacity = lvl.capacity
+	p.tubeNames = fullTubeNames[:p.colors+lvl.empties]
+	p.ballStyles = append(p.ballStyles[:0], p.palette...)
+	p.rd.Shuffle(len(p.ballStyles), func(i, j int) {
+		p.ballStyles[i], p.ballStyles[j] = p.ballStyles[j], p.ballStyles[i]
 	})
-	i := 0
-	for _, tube := range p.tubes {
-		for i < len(p.balls) && !tube.full() {
-			tube.Push(p.balls[i])
-			i++
-		}
-		if i == len(p.balls) {
-			break
-		}
+	g, solution := engine.Generate(p.rd, p.colors, lvl.empties, p.capacity, lvl.minMoves)
+	if lvl.mystery {
+		g.Hide()
 	}
+	p.engine = g
+	p.refreshHint()
+	p.par = len(solution)
+	p.autoUsed = false
+	p.picked = -1
+	p.hint = nil
+	p.autoMoves = nil
 }
 
-func (p *ballSort) pick(tube *Tube) {
-	if p.overBall == nil {
-		if tube.empty() || tube.done() {
-			return
-		}
-		p.overBall = tube.top()
-		tube.holdTop = true
+// refreshHint turns the hint off while any ball is hidden, the solver would tell the hidden colors
+func (p *ballSort) refreshHint() {
+	p.hintKey.SetEnabled(!p.daily && !p.engine.Hiding())
+}
+
+func (p *ballSort) showHint() {
+	moves, ok := p.engine.Solve()
+	if !ok {
+		p.SetError(errNoSolution)
 		return
 	}
+	if len(moves) > 0 {
+		p.hint = &moves[0]
+	}
+}
+
+func (p *ballSort) autoSolve() tea.Cmd {
+	moves, ok := p.engine.Solve()
+	if !ok {
+		p.SetError(errNoSolution)
+		return nil
+	}
+	p.release()
+	p.autoUsed = true
+	p.autoMoves = moves
+	p.ticker++
+	return p.doTick()
+}
+
+func (p *ballSort) doTick() tea.Cmd {
+	ticker := p.ticker
+	return tea.Tick(autoDelay, func(time.Time) tea.Msg {
+		return tickMsg{ticker: ticker}
+	})
+}
+
+func (p *ballSort) autoMove() tea.Cmd {
+	if len(p.autoMoves) == 0 {
+		return nil
+	}
+	m := p.autoMoves[0]
+	p.autoMoves = p.autoMoves[1:]
+	if err := p.engine.Apply(m); err != nil {
+		p.SetError(err)
+		return nil
+	}
+	p.moved()
+	if len(p.autoMoves) == 0 {
+		return nil
+	}
+	return p.doTick()
+}
 
-	if !tube.empty() && p.overBall == tube.top() {
-		tube.holdTop = false
-		p.overBall = nil
+// Moves is the moves taken in the current level
+func (p *ballSort) Moves() int {
+	return p.engine.Steps()
+}
+
+func (p *ballSort) moved() {
+	p.Emit(game.Moved)
+	p.refreshHint()
+	if !p.engine.Won() {
 		return
 	}
-	if tube.empty() || !tube.full() && p.overBall.id == tube.top().id {
-		old := p.getOverBallTube()
-		tube.Push(old.pop())
-		old.holdTop = false
-		p.overBall = nil
+	moves := p.engine.Steps()
+	if p.autoUsed {
+		p.SetStars(totalStars, 0)
+		p.SkipRecord()
+		p.SetSuccess("Sorted by the solver, try it yourself?")
 		return
 	}
-	old := p.getOverBallTube()
-	old.holdTop = false
-	p.overBall = tube.top()
-	tube.holdTop = true
+	stars := 1
+	switch {
+	case moves <= p.par:
+		stars = 3
+	case moves <= p.par*3/2:
+		stars = 2
+	}
+	p.SetStars(totalStars, stars)
+	p.SetSuccess(fmt.Sprintf("Sorted with %d moves, the par is %d.", moves, p.par))
+}
+
+// release puts back the balls picked up
+func (p *ballSort) release() {
+	p.picked = -1
 }
 
-func (b *ballSort) getOverBallTube() *Tube {
-	for _, tube := range b.tubes {
-		if !tube.empty() && tube.top() == b.overBall {
-			return tube
+func (p *ballSort) pick(i int) {
+	switch {
+	case p.picked == -1:
+		if len(p.engine.Tube(i)) > 0 && !p.engine.Done(i) {
+			p.picked = i
 		}
+	case p.picked == i:
+		p.picked = -1
+	case p.engine.Apply(engine.Move{From: p.picked, To: i}) == nil:
+		p.picked = -1
+		p.moved()
+	case len(p.engine.Tube(i)) > 0:
+		p.picked = i
 	}
-	return nil
 }
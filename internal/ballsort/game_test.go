@@ -0,0 +1,51 @@
+package ballsort
+
+import (
+	"testing"
+
+	"github.com/zrcoder/rdor/pkg/gametest"
+)
+
+func TestHintAndPick(t *testing.T) {
+	gametest.New(t, New()).Snapshot("start").
+		Press("t").Snapshot("hint").
+		Press("a").Snapshot("picked").
+		Press("a").Snapshot("released").
+		Golden()
+}
+
+func TestAutoSolve(t *testing.T) {
+	p := New().(*ballSort)
+	d := gametest.New(t, p).Press("o").Snapshot("auto")
+	for len(p.autoMoves) > 0 {
+		d.Send(tickMsg{ticker: p.ticker})
+	}
+	d.Snapshot("solved").Golden()
+}
+
+func TestLevels(t *testing.T) {
+	prev := getLevel(0)
+	for i := 1; i < totalLevels; i++ {
+		lvl := getLevel(i)
+		if lvl.colors < prev.colors {
+			t.Fatalf("level %d has fewer colors than the one before", i+1)
+		}
+		prev = lvl
+	}
+	if first, last := getLevel(0).colors, getLevel(totalLevels-1).colors; first != 3 || last != 7 {
+		t.Errorf("the colors go from %d to %d, want from 3 to 7", first, last)
+	}
+	if getLevel(totalLevels/2).colors == 7 {
+		t.Error("the colors should keep growing past the middle levels")
+	}
+}
+
+func TestNoHintOnHidden(t *testing.T) {
+	p := New().(*ballSort)
+	d := gametest.New(t, p)
+	p.GoToLevel(9)
+	d.Press("t")
+	if p.hint != nil || p.hintKey.Enabled() {
+		t.Error("the hint should be off while any ball is hidden")
+	}
+}
@@ -0,0 +1,37 @@
+package ballsort
+
+const (
+	totalLevels = 300
+	levelsSeed  = 20240101
+	// dailyLevel is the level the daily board takes the colors and capacity from
+	dailyLevel = 225
+)
+
+type level struct {
+	seed     int64
+	colors   int
+	capacity int
+	empties  int // the empty tubes at the beginning
+	minMoves int // the generated board takes at least minMoves to sort
+	mystery  bool
+}
+
+// getLevel ramps up the colors across all the levels, from 3 to 7, with the capacity and the least moves,
+// the board of a level is generated with its own seed, so it's always the same
+func getLevel(i int) level {
+	lvl := level{
+		seed:     levelsSeed + int64(i),
+		colors:   3 + i/(totalLevels/5),
+		capacity: 4,
+		empties:  2,
+		mystery:  i%10 == 9,
+	}
+	switch block := i / 10 % 5; {
+	case block == 3:
+		lvl.capacity = 5
+	case block == 4 && i >= 100:
+		lvl.empties = 1
+	}
+	lvl.minMoves = lvl.colors*(lvl.capacity-1) + i%10/3
+	return lvl
+}
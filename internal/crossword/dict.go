@@ -0,0 +1,27 @@
+package crossword
+
+import (
+	"strings"
+)
+
+// Entry is a word in the dictionary, Reading is the pinyin of an idiom or the part of speech of an English word
+type Entry struct {
+	Word    string
+	Reading string
+	Meaning string
+}
+
+func loadDict(data string) map[string]*Entry {
+	res := map[string]*Entry{}
+	for _, line := range strings.Split(data, "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 {
+			continue
+		}
+		res[fields[0]] = &Entry{Word: fields[0], Reading: fields[1], Meaning: fields[2]}
+	}
+	return res
+}
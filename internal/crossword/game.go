@@ -2,14 +2,17 @@ package crossword
 
 import (
 	"fmt"
+	"math/rand"
 	"path/filepath"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/BurntSushi/toml"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	lg "github.com/charmbracelet/lipgloss"
+	engine "github.com/zrcoder/rdor/pkg/engine/crossword"
 	"github.com/zrcoder/rdor/pkg/game"
 	"github.com/zrcoder/rdor/pkg/grid"
 	"github.com/zrcoder/rdor/pkg/keys"
@@ -17,28 +20,58 @@ import (
 )
 
 func New() game.Game {
-	return &crossword{Base: game.New(name)}
+	return &crossword{Base: game.New(Chinese.name), lang: Chinese}
+}
+
+// NewEnglish returns the crossword of English words
+func NewEnglish() game.Game {
+	return &crossword{Base: game.New(English.name), lang: English}
 }
 
 type crossword struct {
 	*game.Base
-	*Level
+	lang       *Language
 	buf        *strings.Builder
+	hintKey    *key.Binding
+	endlessKey *key.Binding
 	state      string
 	directions []grid.Direction
 	pos        grid.Position
-	blankWord  *Word
 	levels     int
+	start      time.Time
+	finished   bool
+
+	engine        *engine.Game
+	candidatesPos map[byte]int
+
+	// endless mode plays the generated levels
+	endless     bool
+	rd          *rand.Rand
+	generated   *Level
+	generatedAt int
+	endlessCnt  int
 }
 
 func (c *crossword) Init() tea.Cmd {
 	c.RegisterView(c.view)
+	c.RegisterHelp(c.helpInfo)
 	c.loadSummary()
 	c.buf = &strings.Builder{}
-	c.blankWord = &Word{state: WordStateBlank, char: emptyWord}
 	c.directions = []grid.Direction{grid.Down, grid.Right, grid.Up, grid.Left}
+	hintKey := key.NewBinding(
+		key.WithKeys("q"),
+		key.WithHelp("q", "hint"),
+	)
+	c.hintKey = &hintKey
+	endlessKey := key.NewBinding(
+		key.WithKeys("b"),
+		key.WithHelp("b", "endless"),
+	)
+	c.endlessKey = &endlessKey
+	c.rd = rand.New(rand.NewSource(time.Now().UnixNano()))
 	c.ClearGroups()
 	c.AddKeyGroup(game.KeyGroup{&keys.Up, &keys.Left, &keys.Down, &keys.Right})
+	c.AddKeyGroup(game.KeyGroup{c.hintKey, c.endlessKey})
 	c.set(0)
 	return c.Base.Init()
 }
@@ -60,6 +93,15 @@ func (c *crossword) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			c.move(grid.Left)
 		case key.Matches(msg, keys.Right):
 			c.move(grid.Right)
+		case key.Matches(msg, *c.hintKey):
+			if c.hint() {
+				c.checkSuccess()
+			}
+		case key.Matches(msg, *c.endlessKey):
+			c.endless = !c.endless
+			c.generated = nil
+			c.endlessCnt = 0
+			c.set(c.CurrentLevel())
 		default:
 			if msg.Type == tea.KeyEnter {
 				c.pick(-1)
@@ -67,8 +109,9 @@ func (c *crossword) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			if len(msg.Runes) > 0 {
 				letter := byte(unicode.ToUpper(msg.Runes[0]))
-				if i, ok := c.Level.candidatesPos[letter]; ok {
+				if i, ok := c.candidatesPos[letter]; ok {
 					c.pick(i)
+					c.checkSuccess()
 				}
 			}
 		}
@@ -80,19 +123,42 @@ func (c *crossword) view() string {
 	if c.Err != nil {
 		return ""
 	}
-	last := c.state
-	if c.success() {
-		last = lg.JoinHorizontal(lg.Top, last, "  ", successBg.Render(" 成功 "))
-	}
+	last := lg.JoinHorizontal(lg.Top, c.state, "  ", c.starsView(), "  ", style.Help.Render(fmt.Sprintf("✗ %d", c.engine.Mistakes())))
 	return lg.JoinVertical(lg.Left,
 		c.boardView(),
 		c.candidatesView(),
 		"",
-		last)
+		last,
+		"",
+		c.meaningsView())
+}
+
+func (c *crossword) helpInfo() string {
+	return c.lang.help
+}
+
+// stars costs one for every hint and every mistakesPerStar mistakes
+func (c *crossword) stars() int {
+	return max(0, totalStars-c.engine.Hints()-c.engine.Mistakes()/mistakesPerStar)
+}
+
+func (c *crossword) checkSuccess() {
+	if c.finished || !c.engine.Won() {
+		return
+	}
+	c.finished = true
+	elapsed := time.Since(c.start).Round(time.Second)
+	c.SetStars(totalStars, c.stars())
+	c.SetSuccess(fmt.Sprintf(c.lang.successFmt, elapsed, c.engine.Mistakes(), c.engine.Hints()))
+}
+
+func (c *crossword) starsView() string {
+	stars := c.stars()
+	return starStyle.Render(strings.Repeat("★", stars)) + strings.Repeat("☆", totalStars-stars)
 }
 
 func (c *crossword) loadSummary() {
-	path := filepath.Join("levels", "index.toml")
+	path := filepath.Join(c.lang.levelsDir, "index.toml")
 	data, err := lvsFS.ReadFile(path)
 	if err != nil {
 		c.SetError(err)
@@ -109,20 +175,38 @@ func (c *crossword) loadSummary() {
 }
 
 func (c *crossword) set(i int) {
-	path := filepath.Join("levels", fmt.Sprintf("%02d.toml", i))
+	if c.endless {
+		c.setGenerated(i)
+		return
+	}
+	path := filepath.Join(c.lang.levelsDir, LevelFile(i))
 	data, err := lvsFS.ReadFile(path)
 	if err != nil {
 		c.SetError(err)
 		return
 	}
-	c.Level = &Level{crossword: c}
-	err = toml.Unmarshal(data, c.Level)
+	lvl := &Level{}
+	err = toml.Unmarshal(data, lvl)
 	if err != nil {
 		c.SetError(err)
 		return
 	}
-	if c.adapt(); c.Err != nil {
+	if c.load(lvl); c.Err != nil {
 		return
 	}
 	c.state = style.Help.Render(fmt.Sprintf("%d/%d", i+1, c.levels))
 }
+
+// setGenerated generates a new level unless it's a reset
+func (c *crossword) setGenerated(i int) {
+	if c.generated == nil || i != c.generatedAt {
+		c.rd.Seed(c.LevelSeed() + int64(c.endlessCnt))
+		c.generated = c.lang.Generate(c.rd)
+		c.generatedAt = i
+		c.endlessCnt++
+	}
+	if c.load(c.generated); c.Err != nil {
+		return
+	}
+	c.state = style.Help.Render(fmt.Sprintf("∞ %d", c.endlessCnt))
+}
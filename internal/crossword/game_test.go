@@ -0,0 +1,89 @@
+package crossword
+
+import (
+	"os"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/zrcoder/rdor/pkg/gametest"
+)
+
+func TestHints(t *testing.T) {
+	c := New().(*crossword)
+	d := gametest.New(t, c).Snapshot("start").
+		Press("left", "up").Snapshot("moved")
+	for i := 0; !c.engine.Won() && i < 100; i++ {
+		d.Press("q")
+	}
+	d.Snapshot("solved").Golden()
+}
+
+func TestMistakes(t *testing.T) {
+	c := NewEnglish().(*crossword)
+	d := gametest.New(t, c)
+	// fill the current blank with every candidate until one is wrong
+	for i := range c.engine.Candidates() {
+		if c.engine.Mistakes() > 0 {
+			break
+		}
+		d.Press(string(candidatesKeys[i]))
+	}
+	d.Snapshot("mistake").
+		Press("enter").Snapshot("taken back").
+		Press("b").Snapshot("endless").
+		Golden()
+}
+
+func TestLint(t *testing.T) {
+	for _, lang := range []*Language{Chinese, English} {
+		if errs := lang.Lint(os.DirFS(lang.LevelsDir())); len(errs) > 0 {
+			t.Errorf("%s: %v", lang.name, errs)
+		}
+	}
+	tests := []struct {
+		lang *Language
+		want []string
+	}{
+		{Chinese, []string{"需要9行，实际0行", "没有空格要填"}},
+		{English, []string{"9 rows needed, got 0", "no blanks to fill"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lang.name, func(t *testing.T) {
+			errs := tt.lang.lint(&Level{})
+			got := make([]string, len(errs))
+			for i, err := range errs {
+				got[i] = err.Error()
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintFiles(t *testing.T) {
+	dir := fstest.MapFS{
+		"index.toml": {Data: []byte("levels = 101\n")},
+		"100.toml":   {Data: []byte("")},
+		"7.toml":     {Data: []byte("")},
+	}
+	for i := 0; i < 100; i++ {
+		dir[LevelFile(i)] = dir["100.toml"]
+	}
+	var got []string
+	for _, err := range English.Lint(dir) {
+		got = append(got, err.Error())
+	}
+	for _, want := range []string{
+		"100.toml: 9 rows needed, got 0",
+		"7.toml: " + English.msgs.notLevelFile,
+	} {
+		if !slices.Contains(got, want) {
+			t.Errorf("%q not found", want)
+		}
+	}
+	if slices.Contains(got, "index.toml: levels = 101, but there are 102 level files") {
+		t.Error("the files not named by the levels should not count")
+	}
+}
@@ -0,0 +1,299 @@
+package crossword
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"slices"
+	"strings"
+)
+
+const (
+	minWords     = 4
+	maxWords     = 7
+	decoys       = 2
+	layoutTries  = 200
+	blanksTries  = 50
+	maxSolutions = 2
+)
+
+// placement is a word on the board
+type placement struct {
+	word     []rune
+	row, col int
+	vertical bool
+}
+
+func (p placement) cell(k int) (int, int) {
+	if p.vertical {
+		return p.row + k, p.col
+	}
+	return p.row, p.col + k
+}
+
+type board [size][size]rune
+
+// Generate lays out interlocking words from the dictionary on the board,
+// and picks the blanks and the decoys, the level generated has an unique solution.
+func (lang *Language) Generate(rd *rand.Rand) *Level {
+	words := make([]string, 0, len(lang.dict))
+	for w := range lang.dict {
+		if lang.valid(w) {
+			words = append(words, w)
+		}
+	}
+	slices.Sort(words)
+	byChar := map[rune][]string{}
+	for _, w := range words {
+		for _, ch := range w {
+			byChar[ch] = append(byChar[ch], w)
+		}
+	}
+	for {
+		b, placed := layout(rd, words, byChar)
+		if len(placed) < minWords {
+			continue
+		}
+		for tries := 0; tries < blanksTries; tries++ {
+			if lvl := lang.pickBlanks(rd, b, placed, words); lvl != nil {
+				return lvl
+			}
+		}
+	}
+}
+
+func layout(rd *rand.Rand, words []string, byChar map[rune][]string) (*board, []placement) {
+	b := &board{}
+	first := placement{word: []rune(words[rd.Intn(len(words))]), vertical: rd.Intn(2) == 0}
+	first.row, first.col = rd.Intn(size-len(first.word)+1), rd.Intn(size-len(first.word)+1)
+	b.place(first)
+	placed := []placement{first}
+	target := minWords + rd.Intn(maxWords-minWords+1)
+	for tries := 0; tries < layoutTries && len(placed) < target; tries++ {
+		p := placed[rd.Intn(len(placed))]
+		k := rd.Intn(len(p.word))
+		ch := p.word[k]
+		r, c := p.cell(k)
+		cands := byChar[ch]
+		for _, i := range rd.Perm(len(cands)) {
+			word := []rune(cands[i])
+			if slices.ContainsFunc(placed, func(p placement) bool { return string(p.word) == string(word) }) {
+				continue
+			}
+			j := slices.Index(word, ch)
+			next := placement{word: word, row: r, col: c, vertical: !p.vertical}
+			if next.vertical {
+				next.row -= j
+			} else {
+				next.col -= j
+			}
+			if b.canPlace(next) {
+				b.place(next)
+				placed = append(placed, next)
+				break
+			}
+		}
+	}
+	return b, placed
+}
+
+func (b *board) get(r, c int) rune {
+	if r < 0 || r >= size || c < 0 || c >= size {
+		return 0
+	}
+	return b[r][c]
+}
+
+func (b *board) place(p placement) {
+	for k, ch := range p.word {
+		r, c := p.cell(k)
+		b[r][c] = ch
+	}
+}
+
+// canPlace checks that p crosses other words, and no other characters sticks to it
+func (b *board) canPlace(p placement) bool {
+	endR, endC := p.cell(len(p.word) - 1)
+	if p.row < 0 || p.col < 0 || endR >= size || endC >= size {
+		return false
+	}
+	beforeR, beforeC := p.cell(-1)
+	afterR, afterC := p.cell(len(p.word))
+	if b.get(beforeR, beforeC) != 0 || b.get(afterR, afterC) != 0 {
+		return false
+	}
+	crosses := 0
+	for k, ch := range p.word {
+		r, c := p.cell(k)
+		switch b[r][c] {
+		case ch:
+			crosses++
+		case 0:
+			dr, dc := 0, 1
+			if !p.vertical {
+				dr, dc = 1, 0
+			}
+			if b.get(r-dr, c-dc) != 0 || b.get(r+dr, c+dc) != 0 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return crosses > 0 && crosses < len(p.word)
+}
+
+// pickBlanks chooses one or two blanks of every word with distinct characters,
+// and returns nil if the level has more than one solution
+func (lang *Language) pickBlanks(rd *rand.Rand, b *board, placed []placement, words []string) *Level {
+	blanks := map[[2]int]bool{}
+	used := map[rune]bool{}
+	for _, p := range placed {
+		n := 1 + rd.Intn(2)
+		for _, k := range rd.Perm(len(p.word)) {
+			if n == 0 {
+				break
+			}
+			r, c := p.cell(k)
+			if blanks[[2]int{r, c}] {
+				n--
+				continue
+			}
+			if used[b[r][c]] {
+				continue
+			}
+			blanks[[2]int{r, c}] = true
+			used[b[r][c]] = true
+			n--
+		}
+	}
+	type candidate struct {
+		char rune
+		pos  int
+	}
+	cands := make([]candidate, 0, len(blanks)+decoys)
+	for r := 0; r < size; r++ {
+		for c := 0; c < size; c++ {
+			if blanks[[2]int{r, c}] {
+				cands = append(cands, candidate{char: b[r][c], pos: r*size + c})
+			}
+		}
+	}
+	for len(cands) < len(blanks)+decoys {
+		word := []rune(words[rd.Intn(len(words))])
+		ch := word[rd.Intn(len(word))]
+		if !used[ch] {
+			used[ch] = true
+			cands = append(cands, candidate{char: ch, pos: -1})
+		}
+	}
+	if len(cands) > candidatesLimit {
+		return nil
+	}
+	rd.Shuffle(len(cands), func(i, j int) {
+		cands[i], cands[j] = cands[j], cands[i]
+	})
+	lvl := &Level{}
+	chars := make([]rune, len(cands))
+	for i, cand := range cands {
+		chars[i] = cand.char
+		lvl.AnswerPos = append(lvl.AnswerPos, cand.pos)
+	}
+	lvl.Candidates = string(chars)
+	for r := 0; r < size; r++ {
+		row := make([]rune, size)
+		for c := range row {
+			switch {
+			case b[r][c] == 0:
+				row[c] = lang.empty
+			case blanks[[2]int{r, c}]:
+				row[c] = lang.blank
+			default:
+				row[c] = b[r][c]
+			}
+		}
+		lvl.Grid = append(lvl.Grid, string(row))
+	}
+	if lang.solutions(b, blanks, placed, chars) != 1 {
+		return nil
+	}
+	return lvl
+}
+
+// solutions counts the ways to fill the blanks with chars so that every word is in the dictionary,
+// it stops counting at maxSolutions
+func (lang *Language) solutions(b *board, blanks map[[2]int]bool, placed []placement, chars []rune) int {
+	filled := *b
+	cells := make([][2]int, 0, len(blanks))
+	for cell := range blanks {
+		cells = append(cells, cell)
+	}
+	slices.SortFunc(cells, func(x, y [2]int) int {
+		return (x[0]*size + x[1]) - (y[0]*size + y[1])
+	})
+	for _, cell := range cells {
+		filled[cell[0]][cell[1]] = 0
+	}
+	lineOK := func(p placement) bool {
+		word := make([]rune, len(p.word))
+		for k := range word {
+			r, c := p.cell(k)
+			word[k] = filled[r][c]
+			if word[k] == 0 {
+				return true
+			}
+		}
+		_, ok := lang.dict[string(word)]
+		return ok
+	}
+	usedChars := make([]bool, len(chars))
+	cnt := 0
+	var dfs func(i int)
+	dfs = func(i int) {
+		if cnt >= maxSolutions {
+			return
+		}
+		if i == len(cells) {
+			cnt++
+			return
+		}
+		r, c := cells[i][0], cells[i][1]
+		for j, ch := range chars {
+			if usedChars[j] {
+				continue
+			}
+			filled[r][c] = ch
+			if slices.IndexFunc(placed, func(p placement) bool { return !lineOK(p) }) == -1 {
+				usedChars[j] = true
+				dfs(i + 1)
+				usedChars[j] = false
+			}
+			filled[r][c] = 0
+		}
+	}
+	dfs(0)
+	return cnt
+}
+
+// Encode writes the level in the format of the level files
+func (lang *Language) Encode(w io.Writer, l *Level) error {
+	buf := &strings.Builder{}
+	buf.WriteString(lang.header)
+	buf.WriteString("grid = [\n")
+	for i, row := range l.Grid {
+		buf.WriteString(`"` + row + `"`)
+		if i < len(l.Grid)-1 {
+			buf.WriteString(",\n")
+		}
+	}
+	buf.WriteString("]\n")
+	fmt.Fprintf(buf, "candidates = %q\n", l.Candidates)
+	pos := make([]string, len(l.AnswerPos))
+	for i, p := range l.AnswerPos {
+		pos[i] = fmt.Sprint(p)
+	}
+	fmt.Fprintf(buf, "answerPos = [%s]\n", strings.Join(pos, ", "))
+	buf.WriteString("# generated by gen_tools\n")
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
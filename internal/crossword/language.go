@@ -0,0 +1,128 @@
+package crossword
+
+import (
+	_ "embed"
+	"unicode/utf8"
+)
+
+//go:embed idioms.txt
+var idiomsData string
+
+//go:embed words.txt
+var wordsData string
+
+// Language describes the words a crossword is made of and how the board looks
+type Language struct {
+	name      string
+	levelsDir string
+	// empty and blank are the characters for the empty cells and the cells to fill in level files
+	empty, blank rune
+	// space is how an empty cell looks, pad follows every character to keep the cells the same width
+	space, pad     string
+	minLen, maxLen int
+	dict           map[string]*Entry
+	header         string
+	help           string
+	// successFmt formats the elapsed time, the mistakes and the hints
+	successFmt string
+	msgs       messages
+}
+
+// messages are the errors shown in the game and found by Lint, the formats take the arguments noted
+type messages struct {
+	tooManyCandidates string // the limit of the candidates
+	cannotMove        string
+	levelsCount       string // the levels in the index and the level files found
+	noLevelFile       string
+	notLevelFile      string
+	rows              string // the rows needed and the rows found
+	rowLen            string // the row, the characters needed and found
+	noBlanks          string
+	answersCount      string // the candidates and the answer positions
+	notBlank          string // the candidate and its answer position
+	multiAnswers      string // the position
+	noCandidate       string // the row and the column
+	unknownWord       string // the word
+}
+
+var Chinese = &Language{
+	name:      "成语填字",
+	levelsDir: "levels",
+	empty:     '　',
+	blank:     '〇',
+	space:     "　",
+	minLen:    4,
+	maxLen:    4,
+	dict:      loadDict(idiomsData),
+	header:    "# 9✖️9 格子\n# 全角空格‘　’代表空白格，‘〇’代表待填字符\n",
+	help: "用方向键选择空格，按候选字前的字母填入，回车撤回当前格的字。\n" +
+		"每个提示会填入当前格的正确答案，但要花掉一颗星；每填错三次也会扣一颗星。\n" +
+		"按 b 切换无尽模式，关卡由成语词典随机生成。",
+	successFmt: "用时%s，填错%d次，提示%d次。",
+	msgs: messages{
+		tooManyCandidates: "候选字过多，最多%d个",
+		cannotMove:        "无法移动",
+		levelsCount:       "index.toml: levels = %d，但有%d个关卡文件",
+		noLevelFile:       "关卡文件不存在",
+		notLevelFile:      "不是关卡文件，关卡文件应以编号命名，如00.toml",
+		rows:              "需要%d行，实际%d行",
+		rowLen:            "第%d行需要%d个字，实际%d个",
+		noBlanks:          "没有空格要填",
+		answersCount:      "%d个候选字，但有%d个答案位置",
+		notBlank:          "候选字%c的答案位置%d不是空格",
+		multiAnswers:      "空格%d有多个答案",
+		noCandidate:       "第%d行第%d列的空格没有对应的候选字",
+		unknownWord:       "%s不在词典中",
+	},
+}
+
+var English = &Language{
+	name:      "Word Crossword",
+	levelsDir: "words",
+	empty:     '.',
+	blank:     '_',
+	space:     "  ",
+	pad:       " ",
+	minLen:    3,
+	maxLen:    7,
+	dict:      loadDict(wordsData),
+	header:    "# 9x9 grid\n# '.' is an empty cell, '_' is a letter to fill\n",
+	help: "Select a blank with the arrow keys, press the key before a letter to fill it in, enter to take it back.\n" +
+		"Every hint fills the right letter into the current blank, but costs a star, so do every three mistakes.\n" +
+		"Press b to toggle the endless mode, the levels are generated from the dictionary.",
+	successFmt: "Solved in %s with %d mistake(s) and %d hint(s).",
+	msgs: messages{
+		tooManyCandidates: "too many candidates, %d at most",
+		cannotMove:        "can not move",
+		levelsCount:       "index.toml: levels = %d, but there are %d level files",
+		noLevelFile:       "the level file does not exist",
+		notLevelFile:      "not a level file, which should be named by its number like 00.toml",
+		rows:              "%d rows needed, got %d",
+		rowLen:            "row %d needs %d letters, got %d",
+		noBlanks:          "no blanks to fill",
+		answersCount:      "%d candidates, but %d answer positions",
+		notBlank:          "the answer position of candidate %c is %d, which is not a blank",
+		multiAnswers:      "blank %d has more than one answer",
+		noCandidate:       "the blank at row %d column %d has no candidate",
+		unknownWord:       "%s is not in the dictionary",
+	},
+}
+
+// LevelsDir is the directory of the level files, relative to the package
+func (lang *Language) LevelsDir() string {
+	return lang.levelsDir
+}
+
+// cell renders a character on the board
+func (lang *Language) cell(r rune) string {
+	if r == lang.empty {
+		return lang.space
+	}
+	return string(r) + lang.pad
+}
+
+// valid reports whether the word may be on the board
+func (lang *Language) valid(word string) bool {
+	n := utf8.RuneCountInString(word)
+	return n >= lang.minLen && n <= lang.maxLen && n <= size
+}
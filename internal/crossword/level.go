@@ -3,230 +3,173 @@ package crossword
 import (
 	"errors"
 	"fmt"
-	"unicode/utf8"
+	"strings"
+	"time"
 
+	engine "github.com/zrcoder/rdor/pkg/engine/crossword"
+	"github.com/zrcoder/rdor/pkg/game"
 	"github.com/zrcoder/rdor/pkg/grid"
 )
 
+// Level is a level file
 type Level struct {
-	*crossword
-	Grid          []string `toml:"grid"`
-	Candidates    string   `toml:"candidates"`
-	AnswerPos     []int    `toml:"answerPos"`
-	candidates    Candidates
-	candidatesPos map[byte]int
-	blanks        int
-	grid          *grid.Grid[*Word]
+	Grid       []string `toml:"grid"`
+	Candidates string   `toml:"candidates"`
+	AnswerPos  []int    `toml:"answerPos"`
 }
 
-func (l *Level) adapt() {
-	if l.adaptGrid(); l.Err != nil {
-		return
-	}
-	l.adaptCandidates()
+// LevelFile is the name of the file of level i counting from 0, like 00.toml
+func LevelFile(i int) string {
+	return fmt.Sprintf("%02d.toml", i)
 }
 
-func (l *Level) adaptGrid() {
-	lines := l.Grid
-	if len(lines) > size {
-		l.SetError(errors.New("配置中有太多行"))
+// load starts playing the level
+func (c *crossword) load(l *Level) {
+	if n := len([]rune(l.Candidates)); n > candidatesLimit {
+		c.SetError(fmt.Errorf(c.lang.msgs.tooManyCandidates, candidatesLimit))
 		return
 	}
-	l.grid = grid.New[*Word](size, size)
-	for i, row := range lines {
-		if utf8.RuneCountInString(row) > size {
-			l.SetError(fmt.Errorf("第%d行有太多字", i+1))
-			return
-		}
-		for j, v := range []rune(row) {
-			if v == emptyWord {
-				continue
-			}
-			pos := grid.Position{Row: i, Col: j}
-			if v != blankWord {
-				l.grid.Set(pos, &Word{char: v, state: WordStateRight})
-				continue
-			}
-			l.blanks++
-			l.grid.Set(pos, l.blankWord)
-			if l.blanks == 1 {
-				l.pos = pos
-			}
-		}
-	}
-	if l.blanks == 0 {
-		l.SetError(errors.New("没有空格要填"))
-	}
-}
-
-func (l *Level) adaptCandidates() {
-	cfg := l.Candidates
-	n := utf8.RuneCountInString(cfg)
-	if n > candidatesLimit {
-		l.SetError(errors.New("候选字过多"))
+	g, err := engine.New(l.Grid, l.Candidates, l.AnswerPos, c.lang.empty, c.lang.blank)
+	if err != nil {
+		c.SetError(err)
 		return
 	}
-	l.candidates = make([]*Word, n)
-	l.candidatesPos = make(map[byte]int, n)
-	for i, v := range []rune(cfg) {
-		l.candidates[i] = &Word{char: v, candidatePos: i, destPos: l.AnswerPos[i]}
-		l.candidatesPos[candidatesKeys[i]] = i
+	c.engine = g
+	c.candidatesPos = make(map[byte]int, len(l.AnswerPos))
+	for i := range l.AnswerPos {
+		c.candidatesPos[candidatesKeys[i]] = i
 	}
-}
-
-func (l *Level) boardView() string {
-	l.buf.Reset()
-	l.grid.Range(func(pos grid.Position, word *Word, isLineEnd bool) (end bool) {
-		if pos == l.pos && !word.Fixed() {
-			l.buf.WriteString(curBg.Render(string(word.char)))
+	g.Grid().Range(func(pos grid.Position, _ *engine.Cell, _ bool) (end bool) {
+		if !c.fixed(pos) {
+			c.pos = pos
+			return true
+		}
+		return false
+	})
+	c.finished = false
+	c.start = time.Now()
+}
+
+func (c *crossword) cellView(cell *engine.Cell) string {
+	if cell == nil {
+		return c.lang.space
+	}
+	bg := blankBg
+	s := c.lang.cell(cell.Char)
+	switch cell.State {
+	case engine.Right:
+		bg = rightBg
+	case engine.Wrong:
+		bg = wrongBg
+	case engine.Blank:
+		s = c.lang.space
+	}
+	return bg.Render(s)
+}
+
+func (c *crossword) boardView() string {
+	c.buf.Reset()
+	c.engine.Grid().Range(func(pos grid.Position, cell *engine.Cell, isLineEnd bool) (end bool) {
+		if pos == c.pos && !cell.Fixed() {
+			char := c.lang.empty
+			if cell.State != engine.Blank {
+				char = cell.Char
+			}
+			c.buf.WriteString(curBg.Render(c.lang.cell(char)))
 		} else {
-			l.buf.WriteString(word.View())
+			c.buf.WriteString(c.cellView(cell))
 		}
 		if isLineEnd {
-			l.buf.WriteString("\n")
+			c.buf.WriteString("\n")
 		}
 		return false
 	})
-	return boardStyle.Render(l.buf.String())
+	return boardStyle.Render(c.buf.String())
 }
 
-func (l *Level) candidatesView() string {
-	l.buf.Reset()
-	for i, w := range l.candidates {
-		l.buf.WriteByte(candidatesKeys[i])
-		l.buf.WriteRune(':')
-		if w != nil {
-			l.buf.WriteRune(w.char)
+func (c *crossword) candidatesView() string {
+	c.buf.Reset()
+	for i, char := range c.engine.Candidates() {
+		c.buf.WriteByte(candidatesKeys[i])
+		c.buf.WriteRune(':')
+		if char != 0 {
+			c.buf.WriteString(c.lang.cell(char))
 		} else {
-			l.buf.WriteRune(emptyWord)
+			c.buf.WriteString(c.lang.space)
 		}
 		if (i+1)%candidatesPerLine == 0 {
-			l.buf.WriteRune('\n')
+			c.buf.WriteRune('\n')
 		} else {
-			l.buf.WriteRune(emptyWord)
+			c.buf.WriteString(c.lang.space)
 		}
 	}
-	return boardStyle.Render(l.buf.String())
+	return boardStyle.Render(c.buf.String())
 }
 
-func (l *Level) move(d grid.Direction) {
-	pos := l.pos.TransForm(d)
-	cnt := size*size - 1
-	for !l.grid.OutBound(pos) && cnt > 0 {
-		word := l.grid.Get(pos)
-		if word != nil && !word.Fixed() {
-			l.pos = pos
-			return
-		}
-		pos = pos.TransForm(d)
-		cnt--
-	}
-	l.moveToNearestPos(d)
-}
-
-func (l *Level) pick(i int) {
-	if i == -1 {
-		cur := l.curWord()
-		if cur == l.blankWord || cur.Fixed() {
-			return
+func (c *crossword) meaningsView() string {
+	completed := c.engine.Completed()
+	views := make([]string, 0, len(completed))
+	for _, word := range completed {
+		entry, ok := c.lang.dict[word]
+		if !ok {
+			continue
 		}
-		l.setCurWord(l.blankWord)
-		l.candidates.Set(cur)
-		return
-	}
-	word := l.candidates[i]
-	cur := l.curWord()
-	if word == nil || cur != nil && cur.Fixed() {
-		return
-	}
-	l.candidates[i] = nil
-	l.setCurWord(word)
-	if cur.state != WordStateBlank {
-		l.candidates.Set(cur)
+		views = append(views, entry.Word+" "+entry.Reading+"\n"+entry.Meaning)
 	}
-	if !l.check() || l.success() {
-		return
-	}
-	l.moveToNearestPos()
+	return meaningStyle.Render(strings.Join(views, "\n"))
 }
 
-func (l *Level) success() bool {
-	return l.blanks == 0
+// fixed reports whether pos has no cell to fill
+func (c *crossword) fixed(pos grid.Position) bool {
+	cell := c.engine.Grid().Get(pos)
+	return cell == nil || cell.Fixed()
 }
 
-func (l *Level) check() bool {
-	return l.checkHorizental() && l.checkVertical()
-}
-
-func (l *Level) checkHorizental() bool {
-	left, right := l.pos.Col, l.pos.Col
-	for ; left >= 0 && l.grid.Getrc(l.pos.Row, left) != nil && l.grid.Getrc(l.pos.Row, left).state != WordStateBlank; left-- {
-	}
-	for ; right < size && l.grid.Getrc(l.pos.Row, right) != nil && l.grid.Getrc(l.pos.Row, right).state != WordStateBlank; right++ {
-	}
-	if left+1+idiomLen != right {
-		return true
+func (c *crossword) move(d grid.Direction) {
+	g := c.engine.Grid()
+	pos := c.pos.TransForm(d)
+	cnt := engine.Size*engine.Size - 1
+	for !g.OutBound(pos) && cnt > 0 {
+		if !c.fixed(pos) {
+			c.pos = pos
+			return
+		}
+		pos = pos.TransForm(d)
+		cnt--
 	}
-	return l.checkIdiom(l.pos.Row, left+1, l.pos.Row, right-1)
+	c.moveToNearestPos(d)
 }
 
-func (l *Level) checkVertical() bool {
-	up, down := l.pos.Row, l.pos.Row
-	for ; up >= 0 && l.grid.Getrc(up, l.pos.Col) != nil && l.grid.Getrc(up, l.pos.Col).state != WordStateBlank; up-- {
-	}
-	for ; down < size && l.grid.Getrc(down, l.pos.Col) != nil && l.grid.Getrc(down, l.pos.Col).state != WordStateBlank; down++ {
+// pick fills the candidate i into the current cell, -1 takes back the one filled
+func (c *crossword) pick(i int) {
+	mistakes := c.engine.Mistakes()
+	if c.engine.Apply(engine.Move{Pos: c.pos, Candidate: i}) != nil {
+		return
 	}
-	if up+1+idiomLen != down {
-		return true
+	c.Emit(game.Moved)
+	if i != -1 && c.engine.Mistakes() == mistakes && !c.engine.Won() {
+		c.moveToNearestPos()
 	}
-	return l.checkIdiom(up+1, l.pos.Col, down-1, l.pos.Col)
 }
 
-func (l *Level) checkIdiom(startR, startC, endR, endC int) bool {
-	ok := true
-	for i := startR; i <= endR; i++ {
-		for j := startC; j <= endC; j++ {
-			word := l.grid.Getrc(i, j)
-			if !word.Fixed() && word.destPos != i*size+j {
-				ok = false
-				break
-			}
-		}
+// hint puts the right word into the current cell, and it can't be taken back any more
+func (c *crossword) hint() bool {
+	if c.engine.Hint(c.pos) != nil {
+		return false
 	}
-	state := WordStateRight
-	delta := -1
-	if !ok {
-		state = WordStateWrong
-		delta = 0
+	if !c.engine.Won() {
+		c.moveToNearestPos()
 	}
-	for i := startR; i <= endR; i++ {
-		for j := startC; j <= endC; j++ {
-			word := l.grid.Getrc(i, j)
-			if !word.Fixed() {
-				word.state = state
-				l.blanks += delta
-			}
-		}
-	}
-	return ok
+	return true
 }
 
-func (l *Level) moveToNearestPos(dir ...grid.Direction) {
-	pos := l.grid.Nearest(l.pos, l.directions, func(p grid.Position) bool {
-		word := l.grid.Get(p)
-		return word != nil && !word.Fixed()
+func (c *crossword) moveToNearestPos(dir ...grid.Direction) {
+	pos := c.engine.Grid().Nearest(c.pos, c.directions, func(p grid.Position) bool {
+		return !c.fixed(p)
 	}, dir...)
 	if pos == nil {
-		l.SetError(errors.New("无法移动"))
+		c.SetError(errors.New(c.lang.msgs.cannotMove))
 	} else {
-		l.pos = *pos
+		c.pos = *pos
 	}
 }
-
-func (l *Level) curWord() *Word {
-	return l.grid.Get(l.pos)
-}
-
-func (l *Level) setCurWord(w *Word) {
-	l.grid.Set(l.pos, w)
-}
@@ -0,0 +1,175 @@
+package crossword
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Lint checks all the level files and the index in dir,
+// which looks like the embedded levels directory of the language
+func (lang *Language) Lint(dir fs.FS) []error {
+	var errs []error
+	data, err := fs.ReadFile(dir, "index.toml")
+	if err != nil {
+		return []error{err}
+	}
+	ls := &struct{ Levels int }{}
+	if err := toml.Unmarshal(data, ls); err != nil {
+		return []error{fmt.Errorf("index.toml: %w", err)}
+	}
+	// the level files are numbered with 2 digits at least, 00.toml, 01.toml ... 100.toml
+	all, err := fs.Glob(dir, "*.toml")
+	if err != nil {
+		return []error{err}
+	}
+	var files []string
+	for _, name := range all {
+		if name == "index.toml" {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSuffix(name, ".toml"))
+		if err != nil || n < 0 || name != LevelFile(n) {
+			errs = append(errs, fmt.Errorf("%s: %s", name, lang.msgs.notLevelFile))
+			continue
+		}
+		files = append(files, name)
+	}
+	if len(files) != ls.Levels {
+		errs = append(errs, fmt.Errorf(lang.msgs.levelsCount, ls.Levels, len(files)))
+	}
+	for i := 0; i < ls.Levels; i++ {
+		name := LevelFile(i)
+		if !slices.Contains(files, name) {
+			errs = append(errs, fmt.Errorf("%s: %s", name, lang.msgs.noLevelFile))
+		}
+	}
+	for _, name := range files {
+		data, err := fs.ReadFile(dir, name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		lvl := &Level{}
+		if err := toml.Unmarshal(data, lvl); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path.Base(name), err))
+			continue
+		}
+		for _, err := range lang.lint(lvl) {
+			errs = append(errs, fmt.Errorf("%s: %w", path.Base(name), err))
+		}
+	}
+	return errs
+}
+
+// lint checks the grid size, the candidates and the answers,
+// and that every line of the solved grid is a known word
+func (lang *Language) lint(l *Level) []error {
+	var errs []error
+	if len(l.Grid) != size {
+		errs = append(errs, fmt.Errorf(lang.msgs.rows, size, len(l.Grid)))
+	}
+	var solved board
+	blanks := map[int]bool{}
+	for i, row := range l.Grid {
+		if i >= size {
+			break
+		}
+		if n := utf8.RuneCountInString(row); n != size {
+			errs = append(errs, fmt.Errorf(lang.msgs.rowLen, i+1, size, n))
+		}
+		for j, ch := range []rune(row) {
+			if j >= size {
+				break
+			}
+			switch ch {
+			case lang.empty:
+			case lang.blank:
+				blanks[i*size+j] = true
+			default:
+				solved[i][j] = ch
+			}
+		}
+	}
+	if len(blanks) == 0 {
+		errs = append(errs, errors.New(lang.msgs.noBlanks))
+	}
+	candidates := []rune(l.Candidates)
+	if len(candidates) > candidatesLimit {
+		errs = append(errs, fmt.Errorf(lang.msgs.tooManyCandidates, candidatesLimit))
+	}
+	if len(candidates) != len(l.AnswerPos) {
+		errs = append(errs, fmt.Errorf(lang.msgs.answersCount, len(candidates), len(l.AnswerPos)))
+		return errs
+	}
+	covered := map[int]bool{}
+	for i, pos := range l.AnswerPos {
+		if pos == -1 {
+			continue
+		}
+		if !blanks[pos] {
+			errs = append(errs, fmt.Errorf(lang.msgs.notBlank, candidates[i], pos))
+			continue
+		}
+		if covered[pos] {
+			errs = append(errs, fmt.Errorf(lang.msgs.multiAnswers, pos))
+			continue
+		}
+		covered[pos] = true
+		solved[pos/size][pos%size] = candidates[i]
+	}
+	for pos := range blanks {
+		if !covered[pos] {
+			errs = append(errs, fmt.Errorf(lang.msgs.noCandidate, pos/size+1, pos%size+1))
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	for _, word := range solved.words() {
+		if _, ok := lang.dict[word]; !ok {
+			errs = append(errs, fmt.Errorf(lang.msgs.unknownWord, word))
+		}
+	}
+	return errs
+}
+
+// words returns the horizontal and vertical runs longer than one character
+func (b *board) words() []string {
+	var res []string
+	collect := func(run []rune) []rune {
+		if len(run) > 1 {
+			res = append(res, string(run))
+		}
+		return run[:0]
+	}
+	var run []rune
+	for r := 0; r < size; r++ {
+		for c := 0; c < size; c++ {
+			if b[r][c] == 0 {
+				run = collect(run)
+			} else {
+				run = append(run, b[r][c])
+			}
+		}
+		run = collect(run)
+	}
+	for c := 0; c < size; c++ {
+		for r := 0; r < size; r++ {
+			if b[r][c] == 0 {
+				run = collect(run)
+			} else {
+				run = append(run, b[r][c])
+			}
+		}
+		run = collect(run)
+	}
+	return res
+}
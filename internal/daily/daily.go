@@ -0,0 +1,192 @@
+package daily
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zrcoder/rdor/internal/ballsort"
+	"github.com/zrcoder/rdor/internal/maze"
+	"github.com/zrcoder/rdor/internal/npuzzle"
+	"github.com/zrcoder/rdor/internal/point24"
+	"github.com/zrcoder/rdor/pkg/game"
+	"github.com/zrcoder/rdor/pkg/leaderboard"
+	"github.com/zrcoder/rdor/pkg/style"
+)
+
+const (
+	// Name is the name of the game, which the events and the records tell the game by
+	Name       = "Daily"
+	dateLayout = "2006-01-02"
+	recordsKey = "daily"
+)
+
+// challenge is a game of one level, seeded by the date
+type challenge interface {
+	game.Game
+	UseSeed(int64)
+	OnSuccess(func())
+	Moves() int
+}
+
+// record is the best result of a challenge in a day
+type record struct {
+	Seconds int `json:"seconds"`
+	Moves   int `json:"moves"`
+}
+
+func (r record) String() string {
+	return fmt.Sprintf("%s/%d", time.Duration(r.Seconds)*time.Second, r.Moves)
+}
+
+func (r record) better(o record) bool {
+	return r.Seconds < o.Seconds || r.Seconds == o.Seconds && r.Moves < o.Moves
+}
+
+func New() game.Game {
+	return &daily{Base: game.New(Name)}
+}
+
+type daily struct {
+	*game.Base
+	playKey    *key.Binding
+	challenges []func() game.Game
+	names      []string
+	date       string
+	seed       int64
+	player     string
+	// records of the player every day, by the date and then the name of the game
+	records map[string]map[string]record
+}
+
+// records are the records of all the players, by the name of the player
+type records map[string]map[string]map[string]record
+
+func (d *daily) Init() tea.Cmd {
+	d.challenges = []func() game.Game{maze.NewDaily, ballsort.NewDaily, npuzzle.NewDaily, point24.NewDaily}
+	d.names = make([]string, len(d.challenges))
+	keys := make([]string, len(d.challenges))
+	for i, c := range d.challenges {
+		d.names[i] = c().Name()
+		keys[i] = strconv.Itoa(i + 1)
+	}
+	// the day is the same all over the world
+	now := time.Now().UTC()
+	d.date = now.Format(dateLayout)
+	d.seed = int64(now.Year()*10000 + int(now.Month())*100 + now.Day())
+	all := records{}
+	if err := d.Store().Load(recordsKey, &all); err != nil {
+		d.SetError(err)
+	}
+	d.records = all[d.player]
+	if d.records == nil {
+		d.records = map[string]map[string]record{}
+	}
+	playKey := key.NewBinding(
+		key.WithKeys(keys...),
+		key.WithHelp(fmt.Sprintf("1-%d", len(keys)), "play"),
+	)
+	d.playKey = &playKey
+	d.RegisterView(d.view)
+	d.RegisterHelp(d.helpInfo)
+	d.ClearGroups()
+	d.AddKeyGroup(game.KeyGroup{d.playKey})
+	return d.Base.Init()
+}
+
+func (d *daily) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	b, cmd := d.Base.Update(msg)
+	if b != d.Base {
+		return b, cmd
+	}
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, *d.playKey) {
+		i, _ := strconv.Atoi(msg.String())
+		g := d.play(i - 1)
+		return g, g.Init()
+	}
+	return d, cmd
+}
+
+// SetPlayer keeps the records for the player,
+// the challenges keep their own records by the date instead of the leaderboards
+func (d *daily) SetPlayer(name string, _ leaderboard.Board) {
+	d.player = name
+}
+
+// play starts the challenge i, the time counts from now on
+func (d *daily) play(i int) game.Game {
+	c := d.challenges[i]().(challenge)
+	c.SetParent(d)
+	c.SetStore(d.Store())
+	c.UseSeed(d.seed)
+	start := time.Now()
+	c.OnSuccess(func() {
+		d.record(d.names[i], record{Seconds: int(time.Since(start).Seconds()), Moves: c.Moves()})
+	})
+	return c
+}
+
+// record keeps r if it's the best of the day
+func (d *daily) record(name string, r record) {
+	today := d.records[d.date]
+	if today == nil {
+		today = map[string]record{}
+		d.records[d.date] = today
+	}
+	if old, ok := today[name]; ok && !r.better(old) {
+		return
+	}
+	today[name] = r
+	if err := d.save(); err != nil {
+		d.SetError(err)
+	}
+}
+
+func (d *daily) save() error {
+	all := records{}
+	if err := d.Store().Load(recordsKey, &all); err != nil {
+		return err
+	}
+	all[d.player] = d.records
+	return d.Store().Save(recordsKey, all)
+}
+
+// share is the result of the day to paste in chat
+func (d *daily) share() string {
+	parts := []string{"rdor daily " + d.date}
+	for _, name := range d.names {
+		res := "-"
+		if r, ok := d.records[d.date][name]; ok {
+			res = r.String()
+		}
+		parts = append(parts, name+" "+res)
+	}
+	return strings.Join(parts, " | ")
+}
+
+func (d *daily) view() string {
+	lines := make([]string, 0, len(d.names))
+	for i, name := range d.names {
+		res := style.Help.Render("not solved yet")
+		if r, ok := d.records[d.date][name]; ok {
+			res = style.Success.Render(fmt.Sprintf("%s  %d moves", time.Duration(r.Seconds)*time.Second, r.Moves))
+		}
+		lines = append(lines, fmt.Sprintf("%d. %-10s %s", i+1, name, res))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left,
+		d.date,
+		"",
+		strings.Join(lines, "\n"),
+		"",
+		style.Help.Render(d.share()),
+	)
+}
+
+func (d *daily) helpInfo() string {
+	return "Everyone plays the same levels today, the best time and moves of each game are kept.\n" +
+		"Copy the line at the bottom to share the result."
+}
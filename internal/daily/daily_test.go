@@ -0,0 +1,28 @@
+package daily
+
+import (
+	"testing"
+
+	"github.com/zrcoder/rdor/pkg/gametest"
+	"github.com/zrcoder/rdor/pkg/leaderboard"
+	"github.com/zrcoder/rdor/pkg/store"
+)
+
+func TestRecordsByPlayer(t *testing.T) {
+	st := store.Store{Dir: t.TempDir()}
+	start := func(player string) *daily {
+		d := New().(*daily)
+		d.SetStore(st)
+		d.SetPlayer(player, leaderboard.Board{})
+		gametest.New(t, d)
+		return d
+	}
+	alice := start("alice")
+	alice.record(alice.names[0], record{Seconds: 30, Moves: 20})
+	if _, ok := start("alice").records[alice.date][alice.names[0]]; !ok {
+		t.Error("the record should be kept for the player")
+	}
+	if _, ok := start("bob").records[alice.date][alice.names[0]]; ok {
+		t.Error("the record of another player should not show")
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/zrcoder/rdor/internal/crossword"
+)
+
+var levelsRe = regexp.MustCompile(`(?m)^levels = (\d+)$`)
+
+// lintCrosswordLevels checks the crossword levels of all languages and exits with 1 if any problem found
+func lintCrosswordLevels() {
+	failed := false
+	for _, lang := range []*crossword.Language{crossword.Chinese, crossword.English} {
+		dir := filepath.Join("internal", "crossword", lang.LevelsDir())
+		for _, err := range lang.Lint(os.DirFS(dir)) {
+			fmt.Fprintln(os.Stderr, filepath.Join(dir, err.Error()))
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// makeCrosswordLevels appends n generated levels to the crossword levels of lang and updates the index
+func makeCrosswordLevels(lang *crossword.Language, n int) {
+	dir := filepath.Join("internal", "crossword", lang.LevelsDir())
+	indexPath := filepath.Join(dir, "index.toml")
+	index, err := os.ReadFile(indexPath)
+	if err != nil {
+		panic(err)
+	}
+	match := levelsRe.FindSubmatch(index)
+	if match == nil {
+		panic("no levels in " + indexPath)
+	}
+	total, err := strconv.Atoi(string(match[1]))
+	if err != nil {
+		panic(err)
+	}
+	rd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	for i := total; i < total+n; i++ {
+		f, err := os.Create(filepath.Join(dir, crossword.LevelFile(i)))
+		if err != nil {
+			panic(err)
+		}
+		err = lang.Encode(f, lang.Generate(rd))
+		f.Close()
+		if err != nil {
+			panic(err)
+		}
+	}
+	index = levelsRe.ReplaceAll(index, []byte(fmt.Sprintf("levels = %d", total+n)))
+	err = os.WriteFile(indexPath, index, 0o600)
+	if err != nil {
+		panic(err)
+	}
+}
@@ -1,5 +1,24 @@
 package main
 
+import (
+	"flag"
+
+	"github.com/zrcoder/rdor/internal/crossword"
+)
+
 func main() {
-	makeMazeLevelsSumary()
+	idioms := flag.Int("crossword", 0, "generate the given number of crossword levels")
+	words := flag.Int("words", 0, "generate the given number of English word crossword levels")
+	lint := flag.Bool("lint", false, "check the crossword levels")
+	flag.Parse()
+	switch {
+	case *idioms > 0:
+		makeCrosswordLevels(crossword.Chinese, *idioms)
+	case *words > 0:
+		makeCrosswordLevels(crossword.English, *words)
+	case *lint:
+		lintCrosswordLevels()
+	default:
+		makeMazeLevelsSumary()
+	}
 }
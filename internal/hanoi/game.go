@@ -1,12 +1,13 @@
 package hanoi
 
 import (
-	"errors"
 	"fmt"
 	"math/rand"
+	"slices"
 	"strconv"
 	"strings"
 
+	engine "github.com/zrcoder/rdor/pkg/engine/hanoi"
 	"github.com/zrcoder/rdor/pkg/game"
 	"github.com/zrcoder/rdor/pkg/style/color"
 
@@ -16,34 +17,43 @@ import (
 )
 
 const (
-	name = "Hanoi"
+	// Name is the name of the game, which the events and the records tell the game by
+	Name = "Hanoi"
 )
 
-var errCantMove = errors.New("can not move the disk above a smaller one")
+// Levels are the disks of every level
+var Levels = []int{3, 4, 5, 6, 7}
+
+// Level returns the level counting from 0 with the disks, -1 if none
+func Level(disks int) int {
+	return slices.Index(Levels, disks)
+}
 
 func New() game.Game {
-	return &hanoi{Base: game.New(name)}
+	return &hanoi{Base: game.New(Name)}
 }
 
 type hanoi struct {
 	*game.Base
-	levels     []int
-	maxDisks   int
-	pileWidth  int
+	levels    []int
+	maxDisks  int
+	pileWidth int
+	// palette are the styles of the disks, shuffled into diskStyles for every level
+	palette    []lipgloss.Style
 	diskStyles []lipgloss.Style
-	disks      int
-	overDisk   *disk
+	engine     *engine.Game
+	disks      []*disk
+	picked     int // the pile picked up the top disk, -1 if none
 	buf        *strings.Builder
 	pilesKey   *key.Binding
 	piles      []*pile
-	steps      int
 }
 
 func (h *hanoi) Init() tea.Cmd {
-	h.levels = []int{3, 4, 5, 6, 7}
+	h.levels = Levels
 	h.maxDisks = h.levels[len(h.levels)-1]
 	h.pileWidth = diskWidthUnit*(h.maxDisks) + poleWidth
-	h.diskStyles = []lipgloss.Style{
+	h.palette = []lipgloss.Style{
 		lipgloss.NewStyle().Background(color.Red),
 		lipgloss.NewStyle().Background(color.Orange),
 		lipgloss.NewStyle().Background(color.Yellow),
@@ -62,6 +72,7 @@ func (h *hanoi) Init() tea.Cmd {
 	h.ClearGroups()
 	h.AddKeyGroup(game.KeyGroup{h.pilesKey})
 	h.RegisterLevels(len(h.levels), h.setted)
+	h.RegisterMoves(func() int { return h.engine.Steps() })
 	h.buf = &strings.Builder{}
 	return h.Base.Init()
 }
@@ -76,7 +87,7 @@ func (h *hanoi) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch {
 		case key.Matches(msg, *h.pilesKey):
 			h.pick(msg.String())
-			if h.success() {
+			if h.engine.Won() {
 				h.setSuccessView()
 			}
 		}
@@ -96,36 +107,34 @@ func (h *hanoi) helpInfo() string {
 }
 
 func (h *hanoi) setSuccessView() {
-	minSteps := 1<<h.disks - 1
+	steps, minSteps := h.engine.Steps(), h.engine.MinSteps()
 	totalStars := 5
-	if h.steps == minSteps {
-		h.SetSuccess("Fantastic! you earned all the stars!")
+	if steps == minSteps {
 		h.SetStars(totalStars, totalStars)
+		h.SetSuccess("Fantastic! you earned all the stars!")
 		return
 	}
-	s := fmt.Sprintf("Done! Taken %d steps, can you complete it in %d step(s)? ", h.steps, minSteps)
+	s := fmt.Sprintf("Done! Taken %d steps, can you complete it in %d step(s)? ", steps, minSteps)
 	stars := 3
-	if h.steps-minSteps > minSteps/2 {
+	if steps-minSteps > minSteps/2 {
 		stars = 1
 	}
-	h.SetSuccess(s)
 	h.SetStars(totalStars, stars)
+	h.SetSuccess(s)
 }
 
 func (h *hanoi) setted(level int) {
-	h.disks = h.levels[level]
-	h.steps = 0
-	h.overDisk = nil
-	h.piles = make([]*pile, 3)
+	h.engine = engine.New(h.levels[level])
+	h.picked = -1
+	h.piles = make([]*pile, engine.Piles)
 	for i := range h.piles {
-		h.piles[i] = &pile{hanoi: h, name: strconv.Itoa(i + 1)}
+		h.piles[i] = &pile{hanoi: h, index: i, name: strconv.Itoa(i + 1)}
 	}
 	h.shuffleDiskStyles()
-	disks := make([]*disk, h.disks)
-	for i := 1; i <= h.disks; i++ {
-		disks[h.disks-i] = newDisk(i, h.diskStyles[i-1])
+	h.disks = make([]*disk, h.engine.Disks())
+	for i := range h.disks {
+		h.disks[i] = newDisk(i+1, h.diskStyles[i])
 	}
-	h.piles[0].disks = disks
 }
 
 func (h *hanoi) pick(key string) {
@@ -138,31 +147,20 @@ func (h *hanoi) pick(key string) {
 		"l": 2,
 	}
 	i := idx[key]
-	curPile := h.piles[i]
-	if h.overDisk == nil && curPile.empty() {
-		return
-	}
-	if h.overDisk == nil {
-		curPile.overOne = true
-		h.overDisk = curPile.top()
-		return
-	}
-	if !curPile.empty() && h.overDisk.id > curPile.top().id {
-		h.SetError(errCantMove)
-		return
-	}
-	if !curPile.empty() && h.overDisk == curPile.top() {
-		curPile.overOne = false
-		h.overDisk = nil
-		return
-	}
-	for _, p := range h.piles {
-		if p.overOne {
-			h.steps++
-			curPile.push(p.pop())
-			p.overOne = false
-			h.overDisk = nil
+	switch {
+	case h.picked == -1:
+		if len(h.engine.Pile(i)) > 0 {
+			h.picked = i
+		}
+	case h.picked == i:
+		h.picked = -1
+	default:
+		if err := h.engine.Apply(engine.Move{From: h.picked, To: i}); err != nil {
+			h.SetError(err)
+			return
 		}
+		h.picked = -1
+		h.Emit(game.Moved)
 	}
 }
 
@@ -180,7 +178,7 @@ func (h *hanoi) writePoles() {
 }
 
 func (h *hanoi) writeState() {
-	h.writeLine(fmt.Sprintf("steps: %d\n", h.steps))
+	h.writeLine(fmt.Sprintf("steps: %d\n", h.engine.Steps()))
 }
 
 func (h *hanoi) writeLine(s string) {
@@ -192,13 +190,10 @@ func (h *hanoi) writeBlankLine() {
 	h.buf.WriteByte('\n')
 }
 
-func (h *hanoi) success() bool {
-	last := h.piles[len(h.piles)-1]
-	return len(last.disks) == h.disks
-}
-
 func (h *hanoi) shuffleDiskStyles() {
-	rand.Shuffle(len(h.diskStyles), func(i, j int) {
+	rd := rand.New(rand.NewSource(h.LevelSeed()))
+	h.diskStyles = append(h.diskStyles[:0], h.palette...)
+	rd.Shuffle(len(h.diskStyles), func(i, j int) {
 		h.diskStyles[i], h.diskStyles[j] = h.diskStyles[j], h.diskStyles[i]
 	})
 }
@@ -0,0 +1,46 @@
+package hanoi
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/zrcoder/rdor/pkg/gametest"
+)
+
+func TestSolve(t *testing.T) {
+	h := New().(*hanoi)
+	d := gametest.New(t, h).Snapshot("start")
+	var solve func(n, from, via, to int)
+	solve = func(n, from, via, to int) {
+		if n == 0 {
+			return
+		}
+		solve(n-1, from, to, via)
+		d.Press(strconv.Itoa(from+1), strconv.Itoa(to+1))
+		solve(n-1, via, from, to)
+	}
+	solve(h.levels[0], 0, 1, 2)
+	d.Snapshot("solved").Golden()
+}
+
+func TestPickAndIllegal(t *testing.T) {
+	gametest.New(t, New()).
+		Press("n").Snapshot("next level").
+		Press("1").Snapshot("picked").
+		Press("2", "1", "2").Snapshot("larger on smaller").
+		Press("?").Snapshot("help").
+		Golden()
+}
+
+func TestSeededColors(t *testing.T) {
+	h := New().(*hanoi)
+	gametest.New(t, h).Press("n", "n")
+	g := New().(*hanoi)
+	gametest.New(t, g)
+	g.GoToLevel(2)
+	for i := range h.diskStyles {
+		if h.diskStyles[i].GetBackground() != g.diskStyles[i].GetBackground() {
+			t.Fatal("the colors of level 3 differ by the levels played before")
+		}
+	}
+}
@@ -17,40 +17,19 @@ const (
 
 type pile struct {
 	*hanoi
-	name    string
-	disks   []*disk
-	overOne bool
-}
-
-func (p *pile) empty() bool {
-	return len(p.disks) == 0
-}
-
-func (p *pile) push(d *disk) {
-	p.disks = append(p.disks, d)
-}
-
-func (p *pile) pop() *disk {
-	n := len(p.disks)
-	res := p.disks[n-1]
-	p.disks = p.disks[:n-1]
-	return res
-}
-
-func (p *pile) top() *disk {
-	n := len(p.disks)
-	return p.disks[n-1]
+	index int
+	name  string
 }
 
 func (p *pile) view() string {
 	lines := make([]string, p.maxDisks+4)
 	lines[0] = strings.Repeat(" ", p.maxDisks*diskWidthUnit)
-	disks := p.disks
+	disks := p.engine.Pile(p.index)
 	writeDisk := func(i int) {
-		lines[i] = disks[len(disks)-1].view
+		lines[i] = p.disks[disks[len(disks)-1]-1].view
 		disks = disks[:len(disks)-1]
 	}
-	if p.overOne {
+	if p.picked == p.index {
 		writeDisk(1)
 	}
 	for i := p.maxDisks; i > 0; i-- {
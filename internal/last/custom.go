@@ -0,0 +1,137 @@
+package last
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	engine "github.com/zrcoder/rdor/pkg/engine/last"
+	"github.com/zrcoder/rdor/pkg/keys"
+	"github.com/zrcoder/rdor/pkg/style"
+)
+
+const (
+	minSide   = 4
+	minTotal  = 3
+	maxEating = 4
+
+	defaultMistakes = int(engine.DefaultMistakeRate * 100)
+	mistakesStep    = 10
+)
+
+// customField is a rule of the custom game, change moves its value by d
+type customField struct {
+	name   string
+	value  func(lv *level) string
+	change func(lv *level, d int)
+}
+
+var customFields = []customField{
+	{
+		name:  "width",
+		value: func(lv *level) string { return fmt.Sprint(lv.width) },
+		change: func(lv *level, d int) {
+			lv.width = clamp(lv.width+d, minSide, defaultWidth)
+			lv.totalCells = min(lv.totalCells, lv.width*lv.height)
+		},
+	},
+	{
+		name:  "height",
+		value: func(lv *level) string { return fmt.Sprint(lv.height) },
+		change: func(lv *level, d int) {
+			lv.height = clamp(lv.height+d, minSide, defaultHeight)
+			lv.totalCells = min(lv.totalCells, lv.width*lv.height)
+		},
+	},
+	{
+		name:   "total cells",
+		value:  func(lv *level) string { return fmt.Sprint(lv.totalCells) },
+		change: func(lv *level, d int) { lv.totalCells = clamp(lv.totalCells+d, minTotal, lv.width*lv.height) },
+	},
+	{
+		name:   "max eat",
+		value:  func(lv *level) string { return fmt.Sprint(lv.eatingMax) },
+		change: func(lv *level, d int) { lv.eatingMax = clamp(lv.eatingMax+d, 2, maxEating) },
+	},
+	{
+		name:   "misère",
+		value:  func(lv *level) string { return onOff(lv.misere) },
+		change: func(lv *level, _ int) { lv.misere = !lv.misere },
+	},
+	{
+		name:   "game of life",
+		value:  func(lv *level) string { return onOff(!lv.still) },
+		change: func(lv *level, _ int) { lv.still = !lv.still },
+	},
+	{
+		name:   "hard rival",
+		value:  func(lv *level) string { return onOff(lv.hard) },
+		change: func(lv *level, _ int) { lv.hard = !lv.hard },
+	},
+	{
+		name:   "mistakes",
+		value:  func(lv *level) string { return fmt.Sprintf("%d%% by minimax", lv.mistakes) },
+		change: func(lv *level, d int) { lv.mistakes = clamp(lv.mistakes+d*mistakesStep, 0, 100) },
+	},
+}
+
+func clamp(x, lo, hi int) int {
+	return max(lo, min(x, hi))
+}
+
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
+
+// toggleCustom opens or closes the settings of the custom game
+func (l *last) toggleCustom() {
+	l.customizing = !l.customizing
+	if l.customizing {
+		draft := *l.levels[len(l.levels)-1]
+		l.draft = &draft
+		l.field = 0
+	}
+}
+
+// customize handles the keys in the settings
+func (l *last) customize(msg tea.KeyMsg) {
+	switch {
+	case key.Matches(msg, keys.Up):
+		l.field = (l.field - 1 + len(customFields)) % len(customFields)
+	case key.Matches(msg, keys.Down):
+		l.field = (l.field + 1) % len(customFields)
+	case key.Matches(msg, keys.Left):
+		customFields[l.field].change(l.draft, -1)
+	case key.Matches(msg, keys.Right):
+		customFields[l.field].change(l.draft, 1)
+	case msg.Type == tea.KeyEnter:
+		l.applyCustom()
+	}
+}
+
+// applyCustom plays the custom game with the rules set
+func (l *last) applyCustom() {
+	l.customizing = false
+	l.levels[len(l.levels)-1] = l.draft
+	l.rivalPicked = false
+	l.GoToLevel(len(l.levels) - 1)
+}
+
+func (l *last) customView() string {
+	buf := &strings.Builder{}
+	buf.WriteString(style.Title.Render("Custom game") + "\n\n")
+	for i, f := range customFields {
+		line := fmt.Sprintf("%-14s< %s >", f.name, f.value(l.draft))
+		if i == l.field {
+			buf.WriteString(style.Warn.Render("> "+line) + "\n")
+		} else {
+			buf.WriteString(style.Help.Render("  "+line) + "\n")
+		}
+	}
+	buf.WriteString("\n" + style.Help.Render("↑/↓ choose, ←/→ change, enter to play, u to cancel"))
+	return buf.String()
+}
@@ -4,26 +4,30 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	engine "github.com/zrcoder/rdor/pkg/engine/last"
 	"github.com/zrcoder/rdor/pkg/game"
 	"github.com/zrcoder/rdor/pkg/grid"
 	"github.com/zrcoder/rdor/pkg/style"
 	"github.com/zrcoder/rdor/pkg/style/color"
 
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
 const (
-	name         = "Last"
-	width        = 10
-	height       = 10
-	defaultTotal = 30
-	defaultLimit = 2
+	// Name is the name of the game, which the events and the records tell the game by
+	Name          = "Last"
+	defaultWidth  = 10
+	defaultHeight = 10
+	defaultTotal  = 30
+	defaultLimit  = 2
 
 	blank = '.'
 	cell  = 'c'
@@ -42,7 +46,7 @@ var playSyles = []lipgloss.Style{
 }
 
 func New() game.Game {
-	return &last{Base: game.New(name)}
+	return &last{Base: game.New(Name)}
 }
 
 type last struct {
@@ -54,6 +58,15 @@ type last struct {
 	charDic     map[rune]string
 	buf         *strings.Builder
 	numbersKey  *key.Binding
+	secondKey   *key.Binding
+	rivalKey    *key.Binding
+	hotSeatKey  *key.Binding
+	playersKey  *key.Binding
+	customKey   *key.Binding
+	engine      *engine.Game
+	opponents   []engine.Opponent
+	opponent    engine.Opponent
+	mistakes    int // the percent of the mistakes the opponents are made with
 	levels      []*level
 	players     [2]grid.Position
 	commonCells int
@@ -62,21 +75,71 @@ type last struct {
 	levelIndex  int
 	eating      bool
 	setting     bool
+	// the player picked the rival instead of the level's
+	rivalPicked bool
+	colors      [2]int
+	// two players take turns on the same keyboard
+	hotSeat    bool
+	playersCfg *playersConfig
+	score      [2]int
+	rounds     int
+	// nil unless playing with another rdor over network
+	net *netPeer
+	// setting the rules of the custom game
+	customizing bool
+	draft       *level
+	field       int
+	// setting the names and the colors in hot-seat mode
+	settingPlayers bool
+	playersDraft   *playersConfig
+	nameInputs     [2]textinput.Model
+	playerField    int
 }
 
 type tickMsg time.Time
 
 func (l *last) Init() tea.Cmd {
 	l.levels = getDefaultLevers()
-	l.RegisterLevels(len(l.levels), l.setLevel)
+	if l.net != nil {
+		l.RegisterLevels(len(l.levels), l.netSetLevel)
+	} else {
+		l.RegisterLevels(len(l.levels), l.setLevel)
+	}
 	l.RegisterView(l.view)
 	l.rd = rand.New(rand.NewSource(time.Now().UTC().UnixNano()))
+	rivalKey := key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "change rival"),
+	)
+	l.rivalKey = &rivalKey
+	hotSeatKey := key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "two players"),
+	)
+	l.hotSeatKey = &hotSeatKey
+	playersKey := key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "players"),
+	)
+	l.playersKey = &playersKey
+	customKey := key.NewBinding(
+		key.WithKeys("u"),
+		key.WithHelp("u", "custom game"),
+	)
+	l.customKey = &customKey
 	l.buf = &strings.Builder{}
 	cmd := l.lifeTransform()
+	if l.net != nil {
+		cmd = l.net.connect()
+	}
 	return tea.Batch(l.Base.Init(), cmd)
 }
 
 func (l *last) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// the names typed take the letters before the base, whose keys are letters too
+	if l.typingName(msg) {
+		return l, l.setPlayers(msg.(tea.KeyMsg))
+	}
 	b, cmd := l.Base.Update(msg)
 	if b != l.Base {
 		return b, cmd
@@ -85,14 +148,38 @@ func (l *last) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tickMsg:
 		return l, tea.Batch(l.lifeTransform(), l.eat(), cmd)
+	case connectedMsg, message, netErrMsg:
+		return l, tea.Batch(l.netUpdate(msg), cmd)
 	case tea.KeyMsg:
+		if l.customizing {
+			if key.Matches(msg, *l.customKey) {
+				l.toggleCustom()
+			} else {
+				l.customize(msg)
+			}
+			return l, cmd
+		}
+		if l.settingPlayers {
+			return l, tea.Batch(l.setPlayers(msg), cmd)
+		}
 		switch {
+		case key.Matches(msg, *l.customKey):
+			l.toggleCustom()
 		case key.Matches(msg, *l.numbersKey):
 			n, _ := strconv.Atoi(msg.String())
-			l.playerIndex = 0
-			l.eatingLeft = n
-			l.eating = true
-			return l, tea.Batch(l.eat(), cmd)
+			return l, tea.Batch(l.startEating(0, n), cmd)
+		case key.Matches(msg, *l.secondKey):
+			n := 1
+			for secondKeys[n-1] != msg.String() {
+				n++
+			}
+			return l, tea.Batch(l.startEating(1, n), cmd)
+		case key.Matches(msg, *l.rivalKey):
+			l.pickRival()
+		case key.Matches(msg, *l.hotSeatKey):
+			l.toggleHotSeat()
+		case key.Matches(msg, *l.playersKey):
+			l.togglePlayers()
 		default:
 			if !l.setting {
 				return l, cmd
@@ -122,6 +209,12 @@ func (l *last) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (l *last) view() string {
+	if l.customizing {
+		return l.customView()
+	}
+	if l.settingPlayers {
+		return l.playersView()
+	}
 	l.buf.Reset()
 
 	l.grid.Range(func(_ grid.Position, char rune, isLineEnd bool) (end bool) {
@@ -133,11 +226,24 @@ func (l *last) view() string {
 	})
 	l.buf.WriteString("\n")
 
-	l.buf.WriteString(l.currentLevel().shortView() + "\n")
-	if l.setting {
-		l.buf.WriteString(style.Warn.Render("You go first? (y/n)"))
+	if l.hotSeat || l.net != nil {
+		l.buf.WriteString(l.currentLevel().shortView() +
+			style.Help.Render(fmt.Sprintf("  Score: %d : %d", l.score[0], l.score[1])) + "\n")
 	} else {
-		l.buf.WriteString(style.Help.Render("You:") + l.charDic[me] + style.Help.Render(" Rival:") + l.charDic[rival] + " ")
+		l.buf.WriteString(l.currentLevel().shortView() + style.Help.Render("  Rival: "+l.opponent.Name()) + "\n")
+	}
+	switch {
+	case l.net != nil && !l.net.started:
+		l.buf.WriteString(style.Warn.Render(l.net.status()))
+	case l.setting:
+		l.buf.WriteString(style.Warn.Render("You go first? (y/n)"))
+	default:
+		if l.hotSeat {
+			l.buf.WriteString(style.Help.Render(l.playersCfg.Names[0]+":") + l.charDic[me] +
+				style.Help.Render(" "+l.playersCfg.Names[1]+":") + l.charDic[rival] + " ")
+		} else {
+			l.buf.WriteString(style.Help.Render("You:") + l.charDic[me] + style.Help.Render(" Rival:") + l.charDic[rival] + " ")
+		}
 		l.buf.WriteString(style.Help.Render(fmt.Sprintf("Left: %2d  Turn:", l.commonCells+2)))
 		if l.playerIndex == 0 {
 			l.buf.WriteString(l.charDic[me])
@@ -149,11 +255,15 @@ func (l *last) view() string {
 }
 
 func (l *last) setLevel(i int) {
-	l.setting = true // wait for the user to decide whether to get started first
+	l.setting = !l.hotSeat && l.net == nil // wait for the user to decide whether to get started first
 	l.eatingPath = &pathStack{}
 	l.levelIndex = i
+	if l.net == nil {
+		l.rd.Seed(l.LevelSeed())
+	}
 	curLvl := l.currentLevel()
 	l.commonCells = curLvl.totalCells - 2 // minus the 2 plays
+	l.engine = engine.New(l.commonCells, curLvl.eatingMax, curLvl.misere)
 	keys := []string{}
 	for i := 1; i <= curLvl.eatingMax; i++ {
 		keys = append(keys, strconv.Itoa(i))
@@ -164,24 +274,63 @@ func (l *last) setLevel(i int) {
 	)
 	l.numbersKey = &numbersKey
 	l.numbersKey.SetEnabled(false)
+	secondKey := key.NewBinding(
+		key.WithKeys(secondKeys[:curLvl.eatingMax]...),
+		key.WithHelp(fmt.Sprintf("%s-%s", secondKeys[0], secondKeys[curLvl.eatingMax-1]), "cells to eat, player 2"),
+	)
+	l.secondKey = &secondKey
+	l.secondKey.SetEnabled(false)
+	l.rivalKey.SetEnabled(!l.hotSeat && l.net == nil)
+	l.hotSeatKey.SetEnabled(l.net == nil)
+	l.customKey.SetEnabled(l.net == nil)
+	l.playersKey.SetEnabled(l.hotSeat)
 	l.ClearGroups()
-	l.AddKeyGroup(game.KeyGroup{l.numbersKey})
-	l.rd.Shuffle(len(playSyles), func(i, j int) {
-		playSyles[i], playSyles[j] = playSyles[j], playSyles[i]
-	})
+	l.AddKeyGroup(game.KeyGroup{l.numbersKey, l.secondKey})
+	l.AddKeyGroup(game.KeyGroup{l.rivalKey, l.hotSeatKey, l.playersKey, l.customKey})
+	if l.opponents == nil || curLvl.mistakes != l.mistakes {
+		l.setOpponents(curLvl.mistakes)
+	}
+	if !l.rivalPicked {
+		l.opponent = l.opponentNamed(curLvl.rival())
+	}
+	// a win against another rival than the level's is not a result of the level
+	if l.opponent != l.opponentNamed(curLvl.rival()) {
+		l.SkipRecord()
+	}
+	if lr, ok := l.opponent.(engine.Learner); ok {
+		lr.NewGame()
+	}
+	perm := l.rd.Perm(len(playSyles))
+	l.colors = [2]int{perm[0], perm[1]}
+	l.setCharDic()
+	l.genCells()
+	l.playerIndex = 0
+	if l.hotSeat {
+		// take turns to go first
+		l.playerIndex = l.rounds % 2
+		l.setted()
+	} else if l.net != nil && l.net.started {
+		l.setted()
+	}
+}
+
+func (l *last) setCharDic() {
+	colors := l.colors
+	if l.hotSeat {
+		colors = l.playersCfg.Colors
+	}
 	l.charDic = map[rune]string{
 		blank: "     ",
 		cell:  "  ◎  ",
-		me:    playSyles[0].Render("  ◉  "),
-		rival: playSyles[1].Render("  ◉  "),
+		me:    playSyles[colors[0]].Render("  ◉  "),
+		rival: playSyles[colors[1]].Render("  ◉  "),
 	}
-	l.genCells()
-	l.playerIndex = 0
 }
 
 func (l *last) setted() {
 	l.setting = false
 	l.numbersKey.SetEnabled(true)
+	l.secondKey.SetEnabled(l.hotSeat)
 	ks := []string{"1", "2", "3", "4"}
 	l.numbersKey.SetKeys(ks[:l.currentLevel().eatingMax]...)
 	l.numbersKey.SetHelp(fmt.Sprintf("1-%d", l.currentLevel().eatingMax), "cells to eat")
@@ -189,6 +338,7 @@ func (l *last) setted() {
 
 func (l *last) genCells() {
 	l.grid = grid.NewWithString("")
+	width, height := l.currentLevel().width, l.currentLevel().height
 	g := make([][]rune, height)
 	for i := range g {
 		g[i] = make([]rune, width)
@@ -228,9 +378,15 @@ func (l *last) doTick() tea.Cmd {
 }
 
 func (l *last) lifeTransform() tea.Cmd {
-	if l.eating || l.commonCells <= 0 {
+	// the board of a networked game only evolves between the turns to keep in sync
+	if l.eating || l.commonCells <= 0 || l.net != nil || l.currentLevel().still {
 		return nil
 	}
+	l.evolve()
+	return l.doTick()
+}
+
+func (l *last) evolve() {
 	cells := 0
 	l.grid.Range(func(pos grid.Position, char rune, _ bool) (end bool) {
 		l.helpGrid.Set(pos, l.grid.Get(pos))
@@ -258,7 +414,6 @@ func (l *last) lifeTransform() tea.Cmd {
 		l.addCells(l.helpGrid, diff)
 	}
 	l.grid.Copy(l.helpGrid)
-	return l.doTick()
 }
 
 func (l *last) countAliveNeighbours(pos grid.Position) int {
@@ -286,6 +441,7 @@ func (l *last) addCells(g *grid.Grid[rune], n int) {
 
 func (l *last) changeCell(g *grid.Grid[rune], from, to rune) {
 	for {
+		width, height := l.currentLevel().width, l.currentLevel().height
 		i := l.rd.Intn(width * height)
 		pos := grid.Position{Row: i / width, Col: i % width}
 		if g.Get(pos) == from {
@@ -315,10 +471,28 @@ func (l *last) eat() tea.Cmd {
 		l.eatingLeft--
 		l.commonCells--
 	}
-	if l.success() {
-		l.SetSuccess("Your are the last :)")
+	if l.net != nil && l.ended() {
+		l.score[l.winner()]++
+	}
+	if l.hotSeat && l.ended() {
+		l.endRound()
+	} else if l.success() {
+		if l.currentLevel().misere {
+			l.SetSuccess("Your rival ate the last :)")
+		} else {
+			l.SetSuccess("Your are the last :)")
+		}
 	} else if l.fail() {
-		l.SetFailure("Your rival is the last :(")
+		if l.currentLevel().misere {
+			l.SetFailure("You ate the last :(")
+		} else {
+			l.SetFailure("Your rival is the last :(")
+		}
+	}
+	if lr, ok := l.opponent.(engine.Learner); ok && !l.hotSeat && l.net == nil && l.ended() {
+		if err := lr.Learn(l.fail()); err != nil {
+			l.SetError(err)
+		}
 	}
 	if l.eatingLeft == 0 {
 		return l.changeTurn()
@@ -390,25 +564,28 @@ func (l *last) currentLevel() *level {
 func (l *last) changeTurn() tea.Cmd {
 	if l.ended() {
 		l.numbersKey.SetEnabled(false)
+		l.secondKey.SetEnabled(false)
 		return nil
 	}
-	l.playerIndex ^= 1      // 0->1 / 1->0
+	l.playerIndex ^= 1 // 0->1 / 1->0
+	if l.hotSeat {
+		l.eating = false
+		return l.doTick()
+	}
+	if l.net != nil {
+		return l.netChangeTurn()
+	}
 	if l.playerIndex == 1 { // the rival, auto eating
 		l.eating = true
-		if l.canEatRival() {
-			l.eatingLeft = l.commonCells + 1
-		} else if !l.currentLevel().hard {
-			// just take random cells
-			l.eatingLeft = 1 + l.rd.Intn(l.currentLevel().eatingMax)
+		lvl := l.currentLevel()
+		if l.canEatRival() && !lvl.misere {
+			l.eatingLeft = l.engine.Left()
 		} else {
-			// very clever rival
-			total := l.commonCells + 1
-			period := l.currentLevel().eatingMax + 1
-			// the best strategy
-			l.eatingLeft = total % period
-			if l.eatingLeft == 0 {
-				l.eatingLeft = 1 + l.rd.Intn(l.currentLevel().eatingMax)
-			}
+			l.eatingLeft = l.opponent.Eat(l.engine.Left(), lvl.eatingMax, lvl.misere)
+		}
+		if err := l.engine.Apply(l.eatingLeft); err != nil {
+			l.SetError(err)
+			return nil
 		}
 	} else {
 		l.eating = false
@@ -416,20 +593,92 @@ func (l *last) changeTurn() tea.Cmd {
 	return l.doTick()
 }
 
+// pickRival changes the rival to the next one and restarts the level
+func (l *last) pickRival() {
+	i := 0
+	for i < len(l.opponents) && l.opponents[i] != l.opponent {
+		i++
+	}
+	l.opponent = l.opponents[(i+1)%len(l.opponents)]
+	l.rivalPicked = true
+	l.GoToLevel(l.levelIndex)
+}
+
+// setOpponents makes the opponents with the minimax one making mistakes in percent, the rival picked is kept
+func (l *last) setOpponents(mistakes int) {
+	i := slices.Index(l.opponents, l.opponent)
+	l.opponents = engine.NewOpponents(l.rd, l.Store(), engine.OpponentOptions{MistakeRate: float64(mistakes) / 100})
+	l.mistakes = mistakes
+	if i != -1 {
+		l.opponent = l.opponents[i]
+	}
+}
+
+func (l *last) opponentNamed(name string) engine.Opponent {
+	for _, o := range l.opponents {
+		if o.Name() == name {
+			return o
+		}
+	}
+	return l.opponents[0]
+}
+
+// startEating lets player i eat n cells in its turn
+func (l *last) startEating(i, n int) tea.Cmd {
+	if l.eating {
+		l.SetError(errors.New("wait, please"))
+		return nil
+	}
+	if l.hotSeat && i != l.playerIndex {
+		l.SetError(fmt.Errorf("it's %s's turn", l.playersCfg.Names[l.playerIndex]))
+		return nil
+	}
+	if l.net != nil && i != l.playerIndex {
+		l.SetError(errors.New("it's your rival's turn"))
+		return nil
+	}
+	// eating more than left just eats all
+	n = min(n, l.engine.Left())
+	if err := l.engine.Apply(n); err != nil {
+		l.SetError(err)
+		return nil
+	}
+	l.Emit(game.Moved)
+	l.playerIndex = i
+	l.eatingLeft = n
+	l.eating = true
+	if l.net != nil && i == 0 {
+		if err := l.net.send(message{Type: msgEat, N: n}); err != nil {
+			l.SetError(err)
+			return nil
+		}
+	}
+	return l.eat()
+}
+
 func (l *last) ended() bool {
 	return l.commonCells == -1
 }
 
+// success reports whether the player is the last, who ate the rival, or was eaten in misère
 func (l *last) success() bool {
-	return l.ended() && l.playerIndex == 0
+	return l.ended() && l.winner() == 0
+}
+
+// winner is the player ate the other if won, or the other one
+func (l *last) winner() int {
+	if l.engine.Won() {
+		return l.playerIndex
+	}
+	return l.playerIndex ^ 1
 }
 
 func (l *last) fail() bool {
-	return l.ended() && l.playerIndex != 0
+	return l.ended() && !l.success()
 }
 
 func (l *last) canEatRival() bool {
-	return l.commonCells+1 <= l.currentLevel().eatingMax
+	return l.engine.Left() <= l.engine.Max()
 }
 
 func (l *last) currentPlayer() grid.Position {
@@ -0,0 +1,75 @@
+package last
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zrcoder/rdor/pkg/game"
+	"github.com/zrcoder/rdor/pkg/gametest"
+)
+
+// eat lets the animations of eating run to the end, the rival's included
+func eat(d *gametest.Driver, l *last) {
+	for i := 0; l.eating && i < 100; i++ {
+		d.Send(tickMsg(time.Time{}))
+	}
+}
+
+func TestEat(t *testing.T) {
+	l := New().(*last)
+	d := gametest.New(t, l).Snapshot("start").
+		Press("y").Snapshot("go first")
+	d.Press("2")
+	eat(d, l)
+	d.Snapshot("first turn")
+	d.Press("1")
+	eat(d, l)
+	d.Snapshot("second turn").Golden()
+}
+
+func TestSettings(t *testing.T) {
+	gametest.New(t, New()).
+		Press("o").Snapshot("rival").
+		Press("t").Snapshot("two players").
+		Press("u").Snapshot("custom").
+		Golden()
+}
+
+func TestRivalPicked(t *testing.T) {
+	l := New().(*last)
+	wins := 0
+	l.OnEvent(func(e game.Event) {
+		if e.Kind == game.Succeeded {
+			wins++
+		}
+	})
+	d := gametest.New(t, l).Press("o")
+	l.SetSuccess("won")
+	if wins != 0 {
+		t.Error("a win against another rival than the level's should not count")
+	}
+	for range len(l.opponents) - 1 {
+		d.Press("o")
+	}
+	l.SetSuccess("won")
+	if wins != 1 {
+		t.Error("a win against the rival of the level should count")
+	}
+}
+
+func TestPlayers(t *testing.T) {
+	l := New().(*last)
+	d := gametest.New(t, l).
+		Press("t", "c").Snapshot("players").
+		Press("ctrl+u").Type("Rory").Press("down", "right").Snapshot("first set").
+		Press("down", "ctrl+u").Type("Sam N").Press("down", "left", "left").Snapshot("second set").
+		Press("enter").Snapshot("saved")
+	d.Golden()
+	cfg, err := loadPlayers(l.Store())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Names != [2]string{"Rory", "Sam N"} || cfg.Colors != l.playersCfg.Colors {
+		t.Errorf("got %v %v stored, want the players set %v %v", cfg.Names, cfg.Colors, l.playersCfg.Names, l.playersCfg.Colors)
+	}
+}
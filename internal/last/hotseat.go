@@ -0,0 +1,184 @@
+package last
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/zrcoder/rdor/pkg/keys"
+	"github.com/zrcoder/rdor/pkg/store"
+	"github.com/zrcoder/rdor/pkg/style"
+)
+
+const (
+	playersStore = "last-players"
+	maxNameLen   = 12
+	// playerFields are the rows of the players setup, the name and the color of each player
+	playerFields = 4
+)
+
+// the keys of the second player in hot-seat mode, for eating 1-4 cells
+var secondKeys = []string{"7", "8", "9", "0"}
+
+var defaultNames = [2]string{"Player 1", "Player 2"}
+
+// playersConfig is the names and colors of the two players in hot-seat mode,
+// they are set in the players setup and kept in the stored file
+type playersConfig struct {
+	Names  [2]string `json:"names"`
+	Colors [2]int    `json:"colors"` // indexes of playSyles
+	store  store.Store
+}
+
+func loadPlayers(st store.Store) (*playersConfig, error) {
+	cfg := &playersConfig{Names: defaultNames, Colors: [2]int{0, 4}, store: st}
+	if err := st.Load(playersStore, cfg); err != nil {
+		return cfg, err
+	}
+	for i, c := range cfg.Colors {
+		if c < 0 || c >= len(playSyles) {
+			cfg.Colors[i] = i
+		}
+	}
+	return cfg, nil
+}
+
+// changeColor moves the color of player i by d, skipping the other player's
+func (cfg *playersConfig) changeColor(i, d int) {
+	n := len(playSyles)
+	c := ((cfg.Colors[i]+d)%n + n) % n
+	if c == cfg.Colors[i^1] {
+		c = ((c+d)%n + n) % n
+	}
+	cfg.Colors[i] = c
+}
+
+func (cfg *playersConfig) save() error {
+	return cfg.store.Save(playersStore, cfg)
+}
+
+func (l *last) toggleHotSeat() {
+	l.hotSeat = !l.hotSeat
+	l.score = [2]int{}
+	l.rounds = 0
+	if l.hotSeat && l.playersCfg == nil {
+		var err error
+		if l.playersCfg, err = loadPlayers(l.Store()); err != nil {
+			l.SetError(err)
+		}
+	}
+	l.setLevel(l.levelIndex)
+}
+
+// togglePlayers opens or closes the players setup
+func (l *last) togglePlayers() {
+	l.settingPlayers = !l.settingPlayers
+	if !l.settingPlayers {
+		return
+	}
+	draft := *l.playersCfg
+	l.playersDraft = &draft
+	for i, name := range draft.Names {
+		input := textinput.New()
+		input.Prompt = ""
+		input.CharLimit = maxNameLen
+		input.SetValue(name)
+		l.nameInputs[i] = input
+	}
+	l.playerField = 0
+	l.focusName()
+}
+
+// typingName reports whether the keys go to a name, the letters typed should not be taken as the keys of the game
+func (l *last) typingName(msg tea.Msg) bool {
+	km, ok := msg.(tea.KeyMsg)
+	return ok && l.settingPlayers && l.playerField%2 == 0 && (km.Type == tea.KeyRunes || km.Type == tea.KeySpace)
+}
+
+// setPlayers handles the keys in the players setup
+func (l *last) setPlayers(msg tea.KeyMsg) tea.Cmd {
+	i := l.playerField / 2
+	switch {
+	case msg.Type == tea.KeyEsc:
+		l.togglePlayers()
+	case msg.Type == tea.KeyEnter:
+		l.applyPlayers()
+	case key.Matches(msg, keys.Up):
+		l.playerField = (l.playerField - 1 + playerFields) % playerFields
+		l.focusName()
+	case key.Matches(msg, keys.Down):
+		l.playerField = (l.playerField + 1) % playerFields
+		l.focusName()
+	case l.playerField%2 == 1 && key.Matches(msg, keys.Left):
+		l.playersDraft.changeColor(i, -1)
+	case l.playerField%2 == 1 && key.Matches(msg, keys.Right):
+		l.playersDraft.changeColor(i, 1)
+	case l.playerField%2 == 0:
+		var cmd tea.Cmd
+		l.nameInputs[i], cmd = l.nameInputs[i].Update(msg)
+		return cmd
+	}
+	return nil
+}
+
+// focusName lets the input of the name take the keys only if the cursor is on it
+func (l *last) focusName() {
+	for i := range l.nameInputs {
+		if l.playerField == 2*i {
+			l.nameInputs[i].Focus()
+		} else {
+			l.nameInputs[i].Blur()
+		}
+	}
+}
+
+// applyPlayers keeps the players set, a blank name is the default one
+func (l *last) applyPlayers() {
+	l.settingPlayers = false
+	for i, input := range l.nameInputs {
+		l.playersDraft.Names[i] = strings.TrimSpace(input.Value())
+		if l.playersDraft.Names[i] == "" {
+			l.playersDraft.Names[i] = defaultNames[i]
+		}
+	}
+	l.playersCfg = l.playersDraft
+	if err := l.playersCfg.save(); err != nil {
+		l.SetError(err)
+	}
+	l.setCharDic()
+}
+
+func (l *last) playersView() string {
+	buf := &strings.Builder{}
+	buf.WriteString(style.Title.Render("Players") + "\n\n")
+	for field := range playerFields {
+		i := field / 2
+		var line string
+		if field%2 == 0 {
+			line = fmt.Sprintf("%-14s%s", fmt.Sprintf("player %d", i+1), l.nameInputs[i].View())
+		} else {
+			line = fmt.Sprintf("%-14s< %s >", "color", playSyles[l.playersDraft.Colors[i]].Render("◉"))
+		}
+		if field == l.playerField {
+			buf.WriteString(style.Warn.Render("> ") + line + "\n")
+		} else {
+			buf.WriteString("  " + line + "\n")
+		}
+	}
+	buf.WriteString("\n" + style.Help.Render("↑/↓ choose, type the name, ←/→ change the color, enter to save, esc to cancel"))
+	return buf.String()
+}
+
+// endRound counts the score of the winner in hot-seat mode
+func (l *last) endRound() {
+	winner := l.winner()
+	l.score[winner]++
+	l.rounds++
+	// the rounds between two people on one keyboard are not results of the player
+	l.SkipRecord()
+	l.SetSuccess(fmt.Sprintf("%s wins! %s %d : %d %s",
+		l.playersCfg.Names[winner],
+		l.playersCfg.Names[0], l.score[0], l.score[1], l.playersCfg.Names[1]))
+}
@@ -7,18 +7,40 @@ import (
 )
 
 type level struct {
-	id         int
-	totalCells int // 30-50, include the two players
-	eatingMax  int // 2-4 every turn, and the `min` limit is 1
-	hard       bool
+	id            int
+	width, height int
+	totalCells    int // 30-50, include the two players
+	eatingMax     int // 2-4 every turn, and the `min` limit is 1
+	hard          bool
+	opponent      string // the name of the rival, random or perfect for hard levels by default
+	misere        bool   // the player eats the last loses
+	still         bool   // the cells don't evolve like the Game of Life
+	custom        bool
+	mistakes      int // the percent of the random moves of the minimax rival
+}
+
+func (l level) rival() string {
+	switch {
+	case l.opponent != "":
+		return l.opponent
+	case l.hard:
+		return "perfect"
+	}
+	return "random"
 }
 
 func (l level) shortView() string {
 	s := fmt.Sprintf("Level: %d  Total: %d  limit: %d", l.id, l.totalCells, l.eatingMax)
+	if l.custom {
+		s = fmt.Sprintf("Custom %d✗%d  Total: %d  limit: %d", l.width, l.height, l.totalCells, l.eatingMax)
+	}
 	s = style.Help.Render(s)
 	if l.hard {
 		s += style.Warn.Render("  hard")
 	}
+	if l.misere {
+		s += style.Warn.Render("  misère")
+	}
 	return s
 }
 
@@ -27,18 +49,36 @@ func (l level) View() string {
 	return style.Help.Render(s)
 }
 
+// HardLevels are the levels counting from 0 against the hard rivals, the custom one excluded
+func HardLevels() []int {
+	var res []int
+	for i, lv := range getDefaultLevers() {
+		if lv.hard && !lv.custom {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
 func getDefaultLevers() []*level {
-	return []*level{
+	lvs := []*level{
 		// the first hand is advantageous
 		{totalCells: 30, eatingMax: 2},
 		{totalCells: 30, eatingMax: 2, hard: true},
 		// the second hand is advantageous
-		{totalCells: 34, eatingMax: 2},
+		{totalCells: 34, eatingMax: 2, opponent: "greedy"},
 		{totalCells: 34, eatingMax: 2, hard: true},
 		// the first hand is advantageous
-		{totalCells: 40, eatingMax: 3},
+		{totalCells: 40, eatingMax: 3, opponent: "learner"},
 		{totalCells: 40, eatingMax: 3, hard: true},
 		// the second hand is advantageous
 		{totalCells: 56, eatingMax: 4, hard: true},
+		// set by the player
+		{totalCells: defaultTotal, eatingMax: defaultLimit, custom: true},
+	}
+	for _, lv := range lvs {
+		lv.width, lv.height = defaultWidth, defaultHeight
+		lv.mistakes = defaultMistakes
 	}
+	return lvs
 }
@@ -0,0 +1,235 @@
+package last
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/zrcoder/rdor/pkg/game"
+)
+
+const (
+	dialTimeout = 5 * time.Second
+
+	msgStart = "start"
+	msgEat   = "eat"
+)
+
+// message is exchanged as a json line,
+// a start message is sent by the host for every round with the seed of the board,
+// so the two sides generate and transform the same board
+type message struct {
+	Type  string `json:"type"`
+	Seed  int64  `json:"seed,omitempty"`
+	Level int    `json:"level,omitempty"`
+	First int    `json:"first,omitempty"` // 0 if the host goes first, else 1
+	N     int    `json:"n,omitempty"`     // cells eaten
+}
+
+type connectedMsg struct {
+	conn net.Conn
+}
+
+type netErrMsg struct {
+	err error
+}
+
+// netPeer is the connection with the other player, the local player is always 0 and the remote one is 1
+type netPeer struct {
+	addr    string
+	hosting bool
+	conn    net.Conn
+	enc     *json.Encoder
+	dec     *json.Decoder
+	started bool
+	// the eating of the rival received before its turn starts here
+	pending []int
+	// seed returns the seed of a new round by the host
+	seed func() int64
+	// the connection is dropped for an error
+	lost bool
+}
+
+// NewNetwork returns the game of Last played with another rdor over tcp,
+// it listens on addr if hosting, or else dials addr
+func NewNetwork(addr string, hosting bool) game.Game {
+	return &last{Base: game.New(Name + " (network)"), net: &netPeer{addr: addr, hosting: hosting, seed: clockSeed}}
+}
+
+func clockSeed() int64 {
+	return time.Now().UnixNano()
+}
+
+// connect listens right away if hosting, so addr is the one listened on, with the port picked if it was 0
+func (p *netPeer) connect() tea.Cmd {
+	if !p.hosting {
+		return func() tea.Msg {
+			conn, err := net.DialTimeout("tcp", p.addr, dialTimeout)
+			if err != nil {
+				return netErrMsg{err: err}
+			}
+			return connectedMsg{conn: conn}
+		}
+	}
+	ln, err := net.Listen("tcp", p.addr)
+	if err != nil {
+		return func() tea.Msg { return netErrMsg{err: err} }
+	}
+	p.addr = ln.Addr().String()
+	return func() tea.Msg {
+		defer ln.Close()
+		conn, err := ln.Accept()
+		if err != nil {
+			return netErrMsg{err: err}
+		}
+		return connectedMsg{conn: conn}
+	}
+}
+
+func (p *netPeer) send(m message) error {
+	if p.enc == nil {
+		return errors.New("not connected")
+	}
+	return p.enc.Encode(m)
+}
+
+func (p *netPeer) recv() tea.Cmd {
+	return func() tea.Msg {
+		var m message
+		if err := p.dec.Decode(&m); err != nil {
+			return netErrMsg{err: err}
+		}
+		return m
+	}
+}
+
+func (p *netPeer) status() string {
+	if p.lost {
+		return "Disconnected from the rival"
+	}
+	if p.hosting {
+		return "Waiting for the rival on " + p.addr
+	}
+	return "Connecting to " + p.addr
+}
+
+func (l *last) netUpdate(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case connectedMsg:
+		l.net.conn = msg.conn
+		l.net.enc = json.NewEncoder(msg.conn)
+		l.net.dec = json.NewDecoder(msg.conn)
+		if l.net.hosting {
+			l.netStart(l.levelIndex)
+		}
+		return l.net.recv()
+	case message:
+		switch msg.Type {
+		case msgStart:
+			if err := l.checkStart(msg); err != nil {
+				l.netFail(err)
+				return nil
+			}
+			l.applyStart(msg)
+		case msgEat:
+			l.net.pending = append(l.net.pending, msg.N)
+			return tea.Batch(l.takePending(), l.net.recv())
+		}
+		return l.net.recv()
+	case netErrMsg:
+		// the waiting on the connection closed for an earlier error fails too
+		if !l.net.lost {
+			l.netFail(msg.err)
+		}
+	}
+	return nil
+}
+
+// checkStart checks the start message from the other side, which may be malformed
+func (l *last) checkStart(m message) error {
+	if m.Level < 0 || m.Level >= len(l.levels) {
+		return fmt.Errorf("invalid level %d to start", m.Level)
+	}
+	if m.First != 0 && m.First != 1 {
+		return fmt.Errorf("invalid player %d to go first", m.First)
+	}
+	return nil
+}
+
+// netFail drops the connection on an error, the game waits no more for the rival
+func (l *last) netFail(err error) {
+	if l.net.conn != nil {
+		l.net.conn.Close()
+		l.net.conn = nil
+		l.net.enc, l.net.dec = nil, nil
+	}
+	l.net.started = false
+	l.net.lost = true
+	l.net.pending = nil
+	l.SetError(fmt.Errorf("network: %w", err))
+}
+
+// netSetLevel starts a new round by the host, the levels of the other side follow the host
+func (l *last) netSetLevel(i int) {
+	switch {
+	case l.net.conn == nil:
+		l.setLevel(i)
+	case l.net.hosting:
+		l.netStart(i)
+	default:
+		l.SetError(errors.New("the host picks the levels"))
+	}
+}
+
+func (l *last) netStart(i int) {
+	seed := l.net.seed()
+	l.rd.Seed(seed)
+	m := message{Type: msgStart, Seed: seed, Level: i, First: l.rd.Intn(2)}
+	if err := l.net.send(m); err != nil {
+		l.SetError(err)
+		return
+	}
+	l.applyStart(m)
+}
+
+func (l *last) applyStart(m message) {
+	l.rd.Seed(m.Seed)
+	l.net.started = true
+	l.net.pending = nil
+	l.eating = false
+	l.setLevel(m.Level)
+	l.playerIndex = m.First
+	if !l.net.hosting {
+		l.mirror()
+		l.playerIndex ^= 1
+	}
+}
+
+// mirror swaps the players, for the joined side
+func (l *last) mirror() {
+	l.grid.Set(l.players[0], rival)
+	l.grid.Set(l.players[1], me)
+	l.players[0], l.players[1] = l.players[1], l.players[0]
+	l.colors[0], l.colors[1] = l.colors[1], l.colors[0]
+	l.setCharDic()
+}
+
+func (l *last) netChangeTurn() tea.Cmd {
+	l.eating = false
+	if l.commonCells > 0 && !l.currentLevel().still {
+		l.evolve()
+	}
+	return l.takePending()
+}
+
+func (l *last) takePending() tea.Cmd {
+	if l.playerIndex != 1 || l.eating || len(l.net.pending) == 0 {
+		return nil
+	}
+	n := l.net.pending[0]
+	l.net.pending = l.net.pending[1:]
+	return l.startEating(1, n)
+}
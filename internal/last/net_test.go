@@ -0,0 +1,163 @@
+package last
+
+import (
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/zrcoder/rdor/pkg/gametest"
+	"github.com/zrcoder/rdor/pkg/grid"
+)
+
+// run runs cmd and the commands batched in it, and returns the messages in order
+func run(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		var res []tea.Msg
+		for _, c := range batch {
+			res = append(res, run(c)...)
+		}
+		return res
+	}
+	return []tea.Msg{msg}
+}
+
+// receive updates l with the message the command waiting on the network gets
+func receive(t *testing.T, l *last, recv tea.Cmd) tea.Cmd {
+	t.Helper()
+	done := make(chan []tea.Msg, 1)
+	go func() { done <- run(recv) }()
+	select {
+	case msgs := <-done:
+		var cmd tea.Cmd
+		for _, msg := range msgs {
+			_, cmd = l.Update(msg)
+		}
+		return cmd
+	case <-time.After(dialTimeout):
+		t.Fatal("nothing received")
+	}
+	return nil
+}
+
+// finishEating lets the animations of eating run to the end
+func finishEating(l *last) {
+	for i := 0; l.eating && i < 100; i++ {
+		l.Update(tickMsg(time.Time{}))
+	}
+}
+
+// mirrored reports whether the two sides see the same board with the players swapped
+func mirrored(host, client *last) bool {
+	swap := map[rune]rune{me: rival, rival: me}
+	res := true
+	host.grid.Range(func(pos grid.Position, char rune, _ bool) (end bool) {
+		if c, ok := swap[char]; ok {
+			char = c
+		}
+		res = client.grid.Get(pos) == char
+		return !res
+	})
+	return res
+}
+
+func TestNetwork(t *testing.T) {
+	t.Setenv("RDOR_HOME", t.TempDir())
+	host := NewNetwork("127.0.0.1:0", true).(*last)
+	host.net.seed = func() int64 { return gametest.Seed }
+	accept := host.Init()
+	client := NewNetwork(host.net.addr, false).(*last)
+	hostRecv := make(chan tea.Cmd, 1)
+	go func() {
+		var cmd tea.Cmd
+		for _, msg := range run(accept) {
+			_, cmd = host.Update(msg)
+		}
+		hostRecv <- cmd
+	}()
+	clientRecv := receive(t, client, client.Init())
+	// the host starts the round once connected, the client follows
+	recv := <-hostRecv
+	clientRecv = receive(t, client, clientRecv)
+	if !host.net.started || !client.net.started {
+		t.Fatal("the round should start on both sides")
+	}
+	if client.levelIndex != host.levelIndex || client.playerIndex != host.playerIndex^1 {
+		t.Errorf("got level %d and player %d on the client, want %d and %d",
+			client.levelIndex, client.playerIndex, host.levelIndex, host.playerIndex^1)
+	}
+	if !mirrored(host, client) {
+		t.Fatal("the two sides should generate the same board")
+	}
+
+	// the one to go first eats 2 cells, the other side eats the same with the rival
+	mover, watcher, watcherRecv := host, client, clientRecv
+	if host.playerIndex == 1 {
+		mover, watcher, watcherRecv = client, host, recv
+	}
+	left := mover.engine.Left()
+	mover.Update(gametest.Key("2"))
+	if mover.Err != nil {
+		t.Fatal(mover.Err)
+	}
+	finishEating(mover)
+	receive(t, watcher, watcherRecv)
+	finishEating(watcher)
+	for _, l := range []*last{mover, watcher} {
+		if l.engine.Left() != left-2 {
+			t.Errorf("got %d cells left, want %d", l.engine.Left(), left-2)
+		}
+	}
+	if mover.playerIndex != 1 || watcher.playerIndex != 0 {
+		t.Errorf("got players %d and %d to move, want the turn passed to the watcher", mover.playerIndex, watcher.playerIndex)
+	}
+	if !mirrored(host, client) {
+		t.Error("the two sides should keep the same board")
+	}
+	if host.Err != nil || client.Err != nil {
+		t.Errorf("got errors %v and %v", host.Err, client.Err)
+	}
+	host.net.conn.Close()
+	client.net.conn.Close()
+}
+
+func TestNetworkFailures(t *testing.T) {
+	tests := []struct {
+		name string
+		// send writes to the client from the other side of the connection
+		send func(enc *json.Encoder, conn net.Conn)
+	}{
+		{"level out of range", func(enc *json.Encoder, _ net.Conn) { enc.Encode(message{Type: msgStart, Seed: 1, Level: 99}) }},
+		{"negative level", func(enc *json.Encoder, _ net.Conn) { enc.Encode(message{Type: msgStart, Seed: 1, Level: -1}) }},
+		{"invalid first player", func(enc *json.Encoder, _ net.Conn) { enc.Encode(message{Type: msgStart, Seed: 1, First: 2}) }},
+		{"malformed", func(_ *json.Encoder, conn net.Conn) { conn.Write([]byte("}\n")) }},
+		{"disconnected", func(_ *json.Encoder, conn net.Conn) { conn.Close() }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("RDOR_HOME", t.TempDir())
+			client := NewNetwork("127.0.0.1:0", false).(*last)
+			client.Init()
+			local, remote := net.Pipe()
+			defer remote.Close()
+			_, recv := client.Update(connectedMsg{conn: local})
+			go tt.send(json.NewEncoder(remote), remote)
+			receive(t, client, recv)
+			if client.Err == nil {
+				t.Error("expected an error")
+			}
+			if client.net.started || client.net.conn != nil {
+				t.Error("expected the connection dropped")
+			}
+			if !strings.Contains(client.View(), "Disconnected") {
+				t.Error("expected the view to tell the disconnection")
+			}
+		})
+	}
+}
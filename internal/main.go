@@ -3,26 +3,63 @@ package internal
 import (
 	"fmt"
 	"io"
+	"slices"
+	"time"
 
 	"github.com/zrcoder/rdor/internal/ballsort"
 	"github.com/zrcoder/rdor/internal/crossword"
+	"github.com/zrcoder/rdor/internal/daily"
 	"github.com/zrcoder/rdor/internal/hanoi"
 	"github.com/zrcoder/rdor/internal/last"
 	"github.com/zrcoder/rdor/internal/maze"
 	"github.com/zrcoder/rdor/internal/npuzzle"
 	"github.com/zrcoder/rdor/internal/point24"
 	"github.com/zrcoder/rdor/internal/sokoban"
+	"github.com/zrcoder/rdor/pkg/achievement"
 	"github.com/zrcoder/rdor/pkg/game"
+	"github.com/zrcoder/rdor/pkg/leaderboard"
+	"github.com/zrcoder/rdor/pkg/store"
 	"github.com/zrcoder/rdor/pkg/style"
 	"github.com/zrcoder/rdor/pkg/style/color"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
-func Run() error {
-	const title = "Welcome to rdor"
+// Options are the command line options
+type Options struct {
+	// Host or Join is the address to play Last over network
+	Host, Join string
+	// Seed replays the same boards if not 0
+	Seed int64
+	// Store is where the games keep their data
+	Store store.Store
+	// Board keeps the leaderboards shared by all the players
+	Board leaderboard.Board
+	// Player is who plays all along the session, or the player is picked at launch and can be switched if it's empty
+	Player string
+}
+
+const title = "Welcome to rdor"
+
+var playerKey = key.NewBinding(key.WithKeys("ctrl+p"), key.WithHelp("ctrl+p", "switch player"))
+
+// how long a toast of an achievement unlocked shows
+const toastDuration = 3 * time.Second
+
+func Run(opts Options) error {
+	_, err := tea.NewProgram(New(opts), tea.WithAltScreen()).Run()
+	return err
+}
+
+// New returns the launcher, the picker of the players before it,
+// or the game of Last over network if opts asks for it
+func New(opts Options) tea.Model {
+	if opts.Seed != 0 {
+		game.SetSeed(opts.Seed)
+	}
 	items := []list.Item{
 		hanoi.New(),
 		sokoban.New(),
@@ -31,26 +68,51 @@ func Run() error {
 		npuzzle.New(),
 		point24.New(),
 		crossword.New(),
+		crossword.NewEnglish(),
 		ballsort.New(),
+		daily.New(),
 	}
 	m := &rdor{
+		store: opts.Store,
+		board: opts.Board,
 		list: list.New(
 			items,
 			itemDelegate{},
 			// width = screen width, see Update: tea.WindowSizeMsg
 			0,
 			// height = items(limit 10 every page) + title  + keys help + blank lines
-			len(items)%10+7),
+			min(len(items), 10)+7),
 	}
 	m.list.Title = title
 	m.list.Styles.Title = style.Title
 	m.list.SetShowStatusBar(false)
 	m.list.SetFilteringEnabled(false)
+	m.list.AdditionalShortHelpKeys = func() []key.Binding {
+		if m.picker == nil {
+			return []key.Binding{leaderboard.Key, achievement.Key}
+		}
+		return []key.Binding{leaderboard.Key, achievement.Key, playerKey}
+	}
+	m.list.AdditionalFullHelpKeys = m.list.AdditionalShortHelpKeys
 	for _, it := range items {
 		it.(game.Game).SetParent(m)
+		it.(game.Game).SetStore(opts.Store)
+		it.(game.Game).OnEvent(m.track)
 	}
-	_, err := tea.NewProgram(m, tea.WithAltScreen()).Run()
-	return err
+	if opts.Host != "" || opts.Join != "" {
+		g := last.NewNetwork(opts.Host+opts.Join, opts.Host != "")
+		g.SetParent(m)
+		g.SetStore(opts.Store)
+		return g
+	}
+	// the player given can't be switched, like the one logged in over ssh,
+	// who records in the shared leaderboards only with the name bound to the key
+	if opts.Player != "" {
+		m.setPlayer(opts.Player)
+		return m
+	}
+	m.picker = newPicker(opts.Store, m)
+	return m.picker
 }
 
 type itemDelegate struct{}
@@ -77,20 +139,52 @@ func (d itemDelegate) Render(w io.Writer, m list.Model, index int, listItem list
 }
 
 type rdor struct {
-	list list.Model
+	list    list.Model
+	picker  *picker // nil if the player is fixed
+	store   store.Store
+	board   leaderboard.Board
+	tracker *achievement.Tracker
+	err     error
+}
+
+// setPlayer records the successes in all the games for the player, and tracks the achievements of the player
+func (m *rdor) setPlayer(name string) {
+	for _, it := range m.list.Items() {
+		it.(game.Game).SetPlayer(name, m.board)
+	}
+	m.list.Title = title + ", " + name
+	m.tracker, m.err = achievement.NewTracker(m.store, name, achievements())
+}
+
+// track evaluates the achievements on the events of the games
+func (m *rdor) track(e game.Event) {
+	if m.tracker == nil {
+		return
+	}
+	if err := m.tracker.Track(e); err != nil {
+		m.err = err
+	}
 }
 
-func (m rdor) Init() tea.Cmd { return nil }
+func (m *rdor) Init() tea.Cmd { return nil }
 
-func (m rdor) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+func (m *rdor) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.list.SetWidth(msg.Width)
 		return m, nil
 	case tea.KeyMsg:
-		if msg.String() == "enter" {
+		switch {
+		case msg.String() == "enter":
 			it := m.list.SelectedItem().(game.Game)
-			return it, it.Init()
+			return &playing{Game: it, launcher: m}, it.Init()
+		case key.Matches(msg, leaderboard.Key):
+			v := leaderboard.NewView(m.board, m.list.SelectedItem().(game.Game).Name(), 0, m)
+			return v, v.Init()
+		case key.Matches(msg, achievement.Key) && m.tracker != nil:
+			return achievement.NewGallery(m.tracker, m), nil
+		case key.Matches(msg, playerKey) && m.picker != nil:
+			return m.picker, nil
 		}
 	}
 	var cmd tea.Cmd
@@ -98,6 +192,75 @@ func (m rdor) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
-func (m rdor) View() string {
+func (m *rdor) View() string {
+	if m.err != nil {
+		return "\n" + m.list.View() + "\n" + style.Error.Render(m.err.Error())
+	}
 	return "\n" + m.list.View()
 }
+
+// playing is a game started from the launcher, showing the leaderboard of the current level on its key,
+// and the toasts of the achievements unlocked while playing
+type playing struct {
+	game.Game
+	launcher *rdor
+	toasts   []toast
+}
+
+type toast struct {
+	achievement *achievement.Achievement
+	until       time.Time
+}
+
+// toastTimeoutMsg refreshes the view when a toast times out
+type toastTimeoutMsg struct{}
+
+func (p *playing) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(toastTimeoutMsg); ok {
+		now := time.Now()
+		p.toasts = slices.DeleteFunc(p.toasts, func(t toast) bool { return !now.Before(t.until) })
+		return p, nil
+	}
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, leaderboard.Key) {
+		if g, ok := p.Game.(interface {
+			CurrentLevel() int
+			Player() string
+		}); ok && g.Player() != "" {
+			v := leaderboard.NewView(p.launcher.board, p.Name(), g.CurrentLevel(), p)
+			return v, v.Init()
+		}
+	}
+	m, cmd := p.Game.Update(msg)
+	if m != p.Game {
+		return m, cmd
+	}
+	if p.launcher.tracker == nil {
+		return p, cmd
+	}
+	unlocked := p.launcher.tracker.Unlocked()
+	if len(unlocked) == 0 {
+		return p, cmd
+	}
+	until := time.Now().Add(toastDuration)
+	for _, a := range unlocked {
+		p.toasts = append(p.toasts, toast{achievement: a, until: until})
+	}
+	return p, tea.Batch(cmd, tea.Tick(toastDuration, func(time.Time) tea.Msg { return toastTimeoutMsg{} }))
+}
+
+func (p *playing) View() string {
+	now := time.Now()
+	toasts := make([]string, 0, len(p.toasts))
+	for _, t := range p.toasts {
+		if now.Before(t.until) {
+			toasts = append(toasts, achievement.Toast(t.achievement))
+		}
+	}
+	if len(toasts) == 0 {
+		return p.Game.View()
+	}
+	return lipgloss.JoinVertical(lipgloss.Left,
+		p.Game.View(),
+		lipgloss.NewStyle().PaddingLeft(3).Render(lipgloss.JoinVertical(lipgloss.Left, toasts...)),
+	)
+}
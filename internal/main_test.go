@@ -0,0 +1,48 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/zrcoder/rdor/pkg/gametest"
+)
+
+func TestNavigation(t *testing.T) {
+	gametest.New(t, New(Options{Player: "tester"})).Snapshot("launcher").
+		Press("down", "down").Snapshot("maze selected").
+		Press("enter").Snapshot("maze").
+		Press("ctrl+h").Snapshot("back").
+		Press("end").Snapshot("last item").
+		Press("home", "up").Snapshot("first item").
+		Golden()
+}
+
+func TestProfiles(t *testing.T) {
+	gametest.New(t, New(Options{})).Snapshot("no players").
+		Press("enter").Snapshot("empty name").
+		Type("alice").Press("enter").Snapshot("launcher").
+		Press("ctrl+p").Snapshot("switch").
+		Press("down").Type("bob").Press("enter", "ctrl+p").Snapshot("latest first").
+		Press("down", "enter").Snapshot("alice again").
+		Golden()
+}
+
+func TestLeaderboard(t *testing.T) {
+	gametest.New(t, New(Options{Player: "alice"})).
+		Press("enter", "1", "3", "1", "2", "3", "2", "1", "3", "2", "1", "2", "3", "1", "3").Snapshot("solved").
+		Press("ctrl+l").Snapshot("leaderboard").
+		Press("right").Snapshot("only one level").
+		Press("esc").Snapshot("back to the game").
+		Press("n", "ctrl+l").Snapshot("no records").
+		Press("esc", "ctrl+h", "ctrl+l").Snapshot("from the launcher").
+		Golden()
+}
+
+func TestAchievements(t *testing.T) {
+	gametest.New(t, New(Options{Player: "alice"})).
+		Press("ctrl+a").Snapshot("nothing yet").
+		Press("esc", "enter").
+		Press("1", "3", "1", "2", "3", "2", "1", "3").Snapshot("not solved").
+		Press("2", "1", "2", "3", "1", "3").Snapshot("toast").
+		Press("ctrl+h", "ctrl+a").Snapshot("unlocked").
+		Golden()
+}
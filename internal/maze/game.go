@@ -3,9 +3,9 @@ package maze
 import (
 	"math/rand"
 	"strings"
-	"time"
 
 	"github.com/zrcoder/rdor/internal/maze/levels"
+	engine "github.com/zrcoder/rdor/pkg/engine/maze"
 	"github.com/zrcoder/rdor/pkg/game"
 	"github.com/zrcoder/rdor/pkg/grid"
 	"github.com/zrcoder/rdor/pkg/keys"
@@ -15,24 +15,26 @@ import (
 )
 
 const (
-	name           = "Maze"
+	// Name is the name of the game, which the events and the records tell the game by
+	Name           = "Maze"
 	verticalWall   = '┃'
 	horizontalWall = '━'
 	corner         = '•'
 	me             = '⦿'
 	goal           = '❀'
 	blank          = ' '
-)
 
-var (
-	up    = grid.Up
-	down  = grid.Down
-	right = grid.Right.Scale(2)
-	left  = grid.Left.Scale(2)
+	dailyRows = 12
+	dailyCols = 20
 )
 
 func New() game.Game {
-	return &maze{Base: game.New(name)}
+	return &maze{Base: game.New(Name)}
+}
+
+// NewDaily returns the maze of one level, generated with the seed given to UseSeed
+func NewDaily() game.Game {
+	return &maze{Base: game.New(Name), daily: true}
 }
 
 type maze struct {
@@ -42,25 +44,30 @@ type maze struct {
 	downKey  *key.Binding
 	leftKey  *key.Binding
 	rightKey *key.Binding
-	myPos    grid.Position
-	goals    map[grid.Position]bool
-	grid     *grid.Grid[rune]
-	helpGrid *grid.Grid[rune]
-	rand     *rand.Rand
+	engine   *engine.Game
 	buf      *strings.Builder
+	daily    bool
 }
 
 func (m *maze) Init() tea.Cmd {
 	m.RegisterView(m.view)
 	m.RegisterHelp(m.helpInfo)
-	m.RegisterLevels(len(levels.Names), m.load)
+	if m.daily {
+		m.RegisterLevels(1, m.load)
+		m.DisabledNextKey()
+		m.DisabledPrevKey()
+		m.DisabledSetKey()
+	} else {
+		m.RegisterLevels(len(levels.Names), m.load)
+	}
+	m.RegisterMoves(m.Moves)
 	m.charMap = map[rune]rune{
-		'|':   verticalWall,
-		'-':   horizontalWall,
-		'o':   corner,
-		'S':   me,
-		'G':   goal,
-		blank: blank,
+		engine.VerticalWall:   verticalWall,
+		engine.HorizontalWall: horizontalWall,
+		engine.Corner:         corner,
+		engine.Player:         me,
+		engine.Goal:           goal,
+		engine.Blank:          blank,
 	}
 	m.upKey = &keys.Up
 	m.leftKey = &keys.Left
@@ -68,7 +75,6 @@ func (m *maze) Init() tea.Cmd {
 	m.rightKey = &keys.Right
 	m.ClearGroups()
 	m.AddKeyGroup(game.KeyGroup{m.upKey, m.leftKey, m.downKey, m.rightKey})
-	m.rand = rand.New(rand.NewSource(int64(time.Now().UnixNano())))
 	m.buf = &strings.Builder{}
 	return m.Base.Init()
 }
@@ -83,13 +89,13 @@ func (m *maze) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, *m.upKey):
-			m.move(up)
+			m.move(grid.Up)
 		case key.Matches(msg, *m.leftKey):
-			m.move(left)
+			m.move(grid.Left)
 		case key.Matches(msg, *m.downKey):
-			m.move(down)
+			m.move(grid.Down)
 		case key.Matches(msg, *m.rightKey):
-			m.move(right)
+			m.move(grid.Right)
 		}
 	}
 	return m, cmd
@@ -98,8 +104,8 @@ func (m *maze) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m *maze) view() string {
 	m.buf.Reset()
 
-	m.grid.Range(func(pos grid.Position, char rune, isLineEnd bool) (end bool) {
-		m.buf.WriteRune(char)
+	m.engine.Grid().Range(func(pos grid.Position, char rune, isLineEnd bool) (end bool) {
+		m.buf.WriteRune(m.charMap[char])
 		if isLineEnd {
 			m.buf.WriteRune('\n')
 		}
@@ -114,60 +120,34 @@ func (m *maze) helpInfo() string {
 }
 
 func (m *maze) load(i int) {
-	level, err := levels.ReadLevel(levels.Names[i])
+	var level string
+	if m.daily {
+		level = engine.Generate(rand.New(rand.NewSource(m.LevelSeed())), dailyRows, dailyCols)
+	} else {
+		var err error
+		level, err = levels.ReadLevel(levels.Names[i])
+		if err != nil {
+			panic(err)
+		}
+	}
+	g, err := engine.Parse(level)
 	if err != nil {
-		panic(err)
+		panic("invalid level config")
 	}
-	m.goals = map[grid.Position]bool{}
-	m.grid = grid.NewWithString(level)
-	m.helpGrid = m.grid.Copied()
-	m.reMap()
-}
-
-func (m *maze) reset() {
-	m.goals = map[grid.Position]bool{}
-	m.grid.Copy(m.helpGrid)
-	m.reMap()
-}
-
-func (m *maze) reMap() {
-	m.grid.Range(func(pos grid.Position, char rune, isLineEnd bool) (end bool) {
-		v, ok := m.charMap[char]
-		if !ok {
-			panic("invalid level config")
-		}
-		if v == me {
-			m.myPos = pos
-		} else if v == goal {
-			m.goals[pos] = true
-		}
-		m.grid.Set(pos, v)
-		return
-	})
+	m.engine = g
 }
 
 func (m *maze) move(d grid.Direction) {
-	pos := grid.TransForm(m.myPos, d)
-	obj := m.grid.Get(pos)
-	if m.grid.OutBound(pos) || obj == horizontalWall || obj == verticalWall {
+	if m.engine.Apply(d) != nil {
 		return
 	}
-	pos = grid.TransForm(pos, d)
-	m.moveMe(pos)
-	if m.success() {
+	m.Emit(game.Moved)
+	if m.engine.Won() {
 		m.SetSuccess("")
 	}
 }
 
-func (m *maze) moveMe(pos grid.Position) {
-	if m.grid.Get(pos) == goal {
-		delete(m.goals, pos)
-	}
-	m.grid.Set(pos, me)
-	m.grid.Set(m.myPos, blank)
-	m.myPos = pos
-}
-
-func (m *maze) success() bool {
-	return len(m.goals) == 0
+// Moves is the steps taken in the current level
+func (m *maze) Moves() int {
+	return m.engine.Steps()
 }
@@ -0,0 +1,14 @@
+package maze
+
+import (
+	"testing"
+
+	"github.com/zrcoder/rdor/pkg/gametest"
+)
+
+func TestMoves(t *testing.T) {
+	gametest.New(t, New()).Snapshot("start").
+		Press("up", "up", "up", "right", "down").Snapshot("moved").
+		Press("s").Type("3").Press("enter").Snapshot("level 3").
+		Golden()
+}
@@ -3,6 +3,7 @@ package npuzzle
 import (
 	"fmt"
 	"math/rand"
+	"strconv"
 	"strings"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	lg "github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/lipgloss/table"
+	engine "github.com/zrcoder/rdor/pkg/engine/npuzzle"
 	"github.com/zrcoder/rdor/pkg/game"
 	"github.com/zrcoder/rdor/pkg/grid"
 	"github.com/zrcoder/rdor/pkg/keys"
@@ -18,8 +20,9 @@ import (
 )
 
 const (
-	name     = "N-Puzzle"
-	defaultN = 3
+	// Name is the name of the game, which the events and the records tell the game by
+	Name       = "N-Puzzle"
+	totalStars = 3
 )
 
 var (
@@ -28,32 +31,47 @@ var (
 )
 
 func New() game.Game {
-	return &nPuzzle{Base: game.New(name)}
+	return &nPuzzle{Base: game.New(Name)}
+}
+
+// NewDaily returns the puzzle of one level, shuffled with the seed given to UseSeed
+func NewDaily() game.Game {
+	return &nPuzzle{Base: game.New(Name), daily: true}
 }
 
 type nPuzzle struct {
 	*game.Base
 
-	rd         *rand.Rand
-	grid       *grid.Grid[string]
-	state      string
-	downKey    *key.Binding
-	leftKey    *key.Binding
-	upKey      *key.Binding
-	rightKey   *key.Binding
-	rows       []string
-	cols       []string
-	directions []grid.Direction
-	blank      grid.Position
-	n          int
+	rd       *rand.Rand
+	engine   *engine.Game
+	start    time.Time
+	downKey  *key.Binding
+	leftKey  *key.Binding
+	upKey    *key.Binding
+	rightKey *key.Binding
+	levels   []level
+	rows     []string
+	cols     []string
+	n        int
+	optimal  int
+	exact    bool
+	daily    bool
 }
 
 func (p *nPuzzle) Init() tea.Cmd {
 	p.RegisterView(p.view)
-	p.n = defaultN
-	p.RegisterLevels(p.n, p.set)
+	p.levels = getLevels()
+	if p.daily {
+		p.levels = []level{dailyLevel}
+	}
+	p.RegisterLevels(len(p.levels), p.set)
+	p.RegisterMoves(p.Moves)
+	if p.daily {
+		p.DisabledNextKey()
+		p.DisabledPrevKey()
+		p.DisabledSetKey()
+	}
 	p.rd = rand.New(rand.NewSource(time.Now().UTC().UnixNano()))
-	p.directions = []grid.Direction{grid.Up, grid.Left, grid.Down, grid.Right}
 	p.upKey = &keys.Up
 	p.leftKey = &keys.Left
 	p.downKey = &keys.Down
@@ -74,13 +92,13 @@ func (p *nPuzzle) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, *p.upKey):
-			p.move(grid.Down)
-		case key.Matches(msg, *p.downKey):
 			p.move(grid.Up)
+		case key.Matches(msg, *p.downKey):
+			p.move(grid.Down)
 		case key.Matches(msg, *p.leftKey):
-			p.move(grid.Right)
-		case key.Matches(msg, *p.rightKey):
 			p.move(grid.Left)
+		case key.Matches(msg, *p.rightKey):
+			p.move(grid.Right)
 		}
 	}
 	return p, cmd
@@ -89,70 +107,77 @@ func (p *nPuzzle) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (p *nPuzzle) view() string {
 	return lipgloss.JoinVertical(lipgloss.Center,
 		p.boardView(),
-		p.state,
+		style.Help.Render(fmt.Sprintf("%d✗%d  moves: %d", p.n, p.n, p.engine.Steps())),
 	)
 }
 
 func (p *nPuzzle) set(i int) {
-	p.n = i + 3
+	lvl := p.levels[i]
+	p.n = lvl.n
 	p.rows = rows[:p.n]
 	p.cols = cols[:p.n]
-	p.state = style.Help.Render(fmt.Sprintf("%d✗%d", p.n, p.n))
-	g := make([][]string, p.n)
-	for r := range g {
-		g[r] = make([]string, p.n)
-		for c := range g[r] {
-			g[r][c] = p.rows[r] + p.cols[c]
+	p.rd.Seed(p.LevelSeed())
+	if lvl.dist > 0 {
+		var err error
+		if p.engine, err = engine.Shuffled(p.n, lvl.dist, p.rd); err != nil {
+			p.SetError(err)
+			p.engine = engine.Random(p.n, p.rd)
 		}
+	} else {
+		p.engine = engine.Random(p.n, p.rd)
 	}
-	g[p.n-1][p.n-1] = ""
-	p.grid = grid.New[string](len(p.rows), len(p.cols))
-	p.grid.SetData(g)
-	p.blank = grid.Position{Row: p.n - 1, Col: p.n - 1}
-	p.shuffle()
+	p.optimal, p.exact = p.engine.Optimal()
+	p.start = time.Now()
 }
 
 func (p *nPuzzle) boardView() string {
 	t := table.New().Border(lg.NormalBorder()).BorderRow(true).StyleFunc(func(row, col int) lg.Style {
 		return lg.NewStyle().Padding(0, 1)
 	})
-	p.grid.RangeRows(func(r int, row []string, isLast bool) (end bool) {
+	for r := 0; r < p.n; r++ {
+		row := make([]string, p.n)
+		for c := range row {
+			// tile t belongs at row (t-1)/n and column (t-1)%n, and is named after it
+			if tile := p.engine.Tile(r, c); tile != 0 {
+				row[c] = p.rows[(tile-1)/p.n] + p.cols[(tile-1)%p.n]
+			}
+		}
 		t.Row(row...)
-		return false
-	})
+	}
 	return lg.JoinVertical(lg.Center,
 		strings.Join(p.cols, "    "),
 		lg.JoinHorizontal(lg.Center, strings.Join(p.rows, "\n\n"), " ", t.String()))
 }
 
-func (p *nPuzzle) shuffle() {
-	for i := p.n * p.n * 8; i > 0; i-- {
-		p.move(p.directions[p.rd.Intn(len(p.directions))])
+func (p *nPuzzle) move(d grid.Direction) {
+	if p.engine.Apply(d) != nil {
+		return
+	}
+	p.Emit(game.Moved)
+	if p.engine.Won() {
+		p.setSuccessView()
 	}
 }
 
-func (p *nPuzzle) move(d grid.Direction) {
-	pos := grid.TransForm(p.blank, d)
-	if p.grid.OutBound(pos) {
-		return
+func (p *nPuzzle) setSuccessView() {
+	elapsed := time.Since(p.start).Round(time.Second)
+	optimal := strconv.Itoa(p.optimal)
+	if !p.exact {
+		optimal = "at least " + optimal
 	}
-	s := p.grid.Get(pos)
-	p.grid.Set(p.blank, s)
-	p.grid.Set(pos, "")
-	p.blank = pos
-	if p.success() {
-		p.SetSuccess("")
+	moves := p.engine.Steps()
+	stars := 1
+	switch {
+	case moves <= p.optimal:
+		stars = 3
+	case moves <= p.optimal*2:
+		stars = 2
 	}
+	p.SetStars(totalStars, stars)
+	p.SetSuccess(fmt.Sprintf("Solved with %d moves in %s, the optimal solution takes %s moves.", moves, elapsed, optimal))
 }
 
-func (p *nPuzzle) success() bool {
-	res := true
-	p.grid.Range(func(pos grid.Position, s string, isLineEnd bool) (end bool) {
-		if len(s) == 2 && (p.rows[pos.Row][0] != s[0] || p.cols[pos.Col][0] != s[1]) {
-			res = false
-			return true
-		}
-		return false
-	})
-	return res
+// Moves is the moves taken in the current level
+func (p *nPuzzle) Moves() int {
+	return p.engine.Steps()
 }
@@ -0,0 +1,62 @@
+package npuzzle
+
+import (
+	"testing"
+
+	engine "github.com/zrcoder/rdor/pkg/engine/npuzzle"
+	"github.com/zrcoder/rdor/pkg/gametest"
+	"github.com/zrcoder/rdor/pkg/grid"
+)
+
+var dirKeys = map[grid.Direction]string{grid.Up: "up", grid.Down: "down", grid.Left: "left", grid.Right: "right"}
+
+func TestSolve(t *testing.T) {
+	p := New().(*nPuzzle)
+	d := gametest.New(t, p).Snapshot("start")
+	// find an optimal solution on another engine of the same board, the probes would count as moves of the game
+	n := p.engine.Size()
+	tiles := make([]int, 0, n*n)
+	for r := 0; r < n; r++ {
+		for c := 0; c < n; c++ {
+			tiles = append(tiles, p.engine.Tile(r, c))
+		}
+	}
+	g, err := engine.New(n, tiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var path []grid.Direction
+	var search func(depth int) bool
+	search = func(depth int) bool {
+		if g.Won() {
+			return true
+		}
+		if depth == 0 {
+			return false
+		}
+		for _, m := range g.Moves() {
+			g.Apply(m)
+			path = append(path, m)
+			if search(depth - 1) {
+				return true
+			}
+			path = path[:len(path)-1]
+			g.Apply(m.Opposite())
+		}
+		return false
+	}
+	if !search(p.optimal) {
+		t.Fatalf("no solution in %d moves", p.optimal)
+	}
+	for _, m := range path {
+		d.Press(dirKeys[m])
+	}
+	d.Snapshot("solved").Golden()
+}
+
+func TestLevels(t *testing.T) {
+	gametest.New(t, New()).
+		Press("up", "left").Snapshot("moved").
+		Press("p").Snapshot("last level").
+		Golden()
+}
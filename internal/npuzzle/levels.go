@@ -0,0 +1,23 @@
+package npuzzle
+
+type level struct {
+	n    int
+	dist int // the optimal moves of the shuffled board, 0 means a uniformly random board
+}
+
+// dailyLevel is the only level of the daily challenge
+var dailyLevel = level{n: 4, dist: 30}
+
+func getLevels() []level {
+	return []level{
+		{n: 3, dist: 8},
+		{n: 3, dist: 14},
+		{n: 3, dist: 20},
+		{n: 3},
+		{n: 4, dist: 20},
+		{n: 4, dist: 30},
+		{n: 4},
+		{n: 5, dist: 30},
+		{n: 5},
+	}
+}
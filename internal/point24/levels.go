@@ -1,11 +1,106 @@
 package point24
 
-func getLevers() [][4]int {
-	return [][4]int{
-		{7, 7, 2, 4},
-		{4, 3, 9, 7},
-		{7, 13, 13, 5},
-		{4, 4, 11, 9},
-		{10, 11, 7, 2},
+import (
+	"math/rand"
+
+	engine "github.com/zrcoder/rdor/pkg/engine/point24"
+	"github.com/zrcoder/rdor/pkg/style"
+)
+
+const (
+	maxDealTries = 20
+	// maxDeals bounds the deals for a solvable hand, the fallback hand is dealt after
+	maxDeals = 1000
+)
+
+type difficulty int
+
+const (
+	easy difficulty = iota
+	normal
+	hard
+)
+
+func (d difficulty) String() string {
+	switch d {
+	case easy:
+		return style.Success.Render("easy")
+	case normal:
+		return style.Help.Render("normal")
+	default:
+		return style.Warn.Render("hard")
+	}
+}
+
+// rate tells how hard a hand of n cards is by the number of its distinct solutions
+func rate(n, solutions int) difficulty {
+	switch {
+	case solutions >= 2*n:
+		return easy
+	case solutions >= n-1:
+		return normal
+	default:
+		return hard
+	}
+}
+
+type level struct {
+	hand       []int
+	solutions  []*engine.Expr
+	difficulty difficulty
+}
+
+func getDifficulties() []difficulty {
+	return []difficulty{easy, easy, easy, normal, normal, normal, hard, hard, hard, hard}
+}
+
+// newDeck returns the 52 cards' values, without the jokers
+func newDeck() []int {
+	res := make([]int, 0, 52)
+	for v := 1; v <= 13; v++ {
+		for suit := 0; suit < 4; suit++ {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// deal draws random hands of n cards from a new deck until a solvable one with difficulty d,
+// after maxDealTries any solvable hand is fine, as some difficulties are rare for the settings,
+// the hand only depends on rd, not on the hands dealt before,
+// the fallback hand is dealt if no solvable hand is drawn in maxDeals
+func deal(rd *rand.Rand, n, target int, d difficulty) *level {
+	deck := newDeck()
+	for tries := 0; tries < maxDeals; tries++ {
+		rd.Shuffle(len(deck), func(i, j int) {
+			deck[i], deck[j] = deck[j], deck[i]
+		})
+		hand := deck[:n]
+		solutions := engine.Solve(hand, target)
+		if len(solutions) == 0 {
+			continue
+		}
+		if actual := rate(n, len(solutions)); actual == d || tries >= maxDealTries {
+			return &level{hand: append([]int(nil), hand...), solutions: solutions, difficulty: actual}
+		}
+	}
+	hand := fallbackHand(n, target)
+	solutions := engine.Solve(hand, target)
+	return &level{hand: hand, solutions: solutions, difficulty: rate(n, len(solutions))}
+}
+
+// fallbackHand returns a hand of n cards known to be solvable:
+// two cards multiplied to the target, times the ones left
+func fallbackHand(n, target int) []int {
+	hand := make([]int, n)
+	for i := range hand {
+		hand[i] = 1
+	}
+	for a := 1; a <= 13; a++ {
+		if target%a == 0 && target/a <= 13 {
+			hand[0], hand[1] = a, target/a
+			break
+		}
 	}
+	return hand
 }
@@ -1,47 +1,140 @@
 package point24
 
 import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
 	"strconv"
+	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	lg "github.com/charmbracelet/lipgloss"
+	engine "github.com/zrcoder/rdor/pkg/engine/point24"
 	"github.com/zrcoder/rdor/pkg/game"
 	"github.com/zrcoder/rdor/pkg/keyblock"
+	"github.com/zrcoder/rdor/pkg/style"
 	"github.com/zrcoder/rdor/pkg/style/color"
 )
 
 const (
-	name  = "24 Points"
-	dest  = 24
-	plus  = "+"
-	minus = "-"
-	times = "×"
-	divid = "÷"
+	// Name is the name of the game, which the events and the records tell the game by
+	Name = "24 Points"
+
+	defaultCards  = 4
+	minCards      = 3
+	roundDuration = 3 * time.Minute
 )
 
 var (
 	resStyle     = lg.NewStyle().Foreground(color.Orange).Border(lg.NormalBorder(), false, false, true, false)
 	successStyle = lg.NewStyle().Foreground(color.Green).Border(lg.NormalBorder(), false, false, true, false)
+
+	numKeys = []string{"a", "s", "d", "f", "z", "x"}
+	targets = []int{24, 10, 12, 36, 48, 100}
+
+	errPickNumber = errors.New("pick a number first")
 )
 
 type point24 struct {
 	*game.Base
-	levels [][4]int
-	oper   string
-	nums   keyblock.KeysLine
-	opers  keyblock.KeysLine
-	num    int
+	rd           *rand.Rand
+	deadline     time.Time
+	undoKey      *key.Binding
+	giveUpKey    *key.Binding
+	solutionKey  *key.Binding
+	cardsKey     *key.Binding
+	targetKey    *key.Binding
+	timedKey     *key.Binding
+	levels       []*level
+	difficulties []difficulty
+	level        *level
+	engine       *engine.Game
+	nums         keyblock.KeysLine
+	opers        keyblock.KeysLine
+	oper         string
+	lastSkip     string
+	picked       int
+	gaveUp       bool
+	showSolution bool
+	cardsCnt     int
+	target       int
+	timed        bool
+	ticker       int
+	score        int
+	solved       int
+	timeUp       bool
+	daily        bool
 }
 
+type tickMsg struct{ ticker int }
+
 func New() game.Game {
-	return &point24{Base: game.New(name)}
+	return &point24{Base: game.New(Name)}
+}
+
+// NewDaily returns the game of one hard hand, dealt with the seed given to UseSeed
+func NewDaily() game.Game {
+	return &point24{Base: game.New(Name), daily: true}
 }
 
 func (p *point24) Init() tea.Cmd {
-	p.levels = getLevers()
+	p.rd = rand.New(rand.NewSource(time.Now().UTC().UnixNano()))
+	p.difficulties = getDifficulties()
+	if p.daily {
+		p.difficulties = []difficulty{hard}
+	}
+	p.cardsCnt = defaultCards
+	p.target = targets[0]
+	p.dealLevels()
 	p.RegisterView(p.view)
+	p.RegisterHelp(p.helpInfo)
 	p.RegisterLevels(len(p.levels), p.setLever)
+	p.RegisterMoves(p.Moves)
 	p.DisabledSetKey()
+	undoKey := key.NewBinding(
+		key.WithKeys("u"),
+		key.WithHelp("u", "undo"),
+	)
+	p.undoKey = &undoKey
+	giveUpKey := key.NewBinding(
+		key.WithKeys("g"),
+		key.WithHelp("g", "give up"),
+	)
+	p.giveUpKey = &giveUpKey
+	solutionKey := key.NewBinding(
+		key.WithKeys("v"),
+		key.WithHelp("v", "show solution"),
+	)
+	p.solutionKey = &solutionKey
+	p.solutionKey.SetEnabled(false)
+	cardsKey := key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "cards count"),
+	)
+	p.cardsKey = &cardsKey
+	targetKey := key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "target"),
+	)
+	p.targetKey = &targetKey
+	timedKey := key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "timed mode"),
+	)
+	p.timedKey = &timedKey
+	p.ClearGroups()
+	p.AddKeyGroup(game.KeyGroup{p.undoKey, p.giveUpKey, p.solutionKey})
+	if p.daily {
+		p.DisabledNextKey()
+		p.DisabledPrevKey()
+		p.cardsKey.SetEnabled(false)
+		p.targetKey.SetEnabled(false)
+		p.timedKey.SetEnabled(false)
+	} else {
+		p.AddKeyGroup(game.KeyGroup{p.cardsKey, p.targetKey, p.timedKey})
+	}
 
 	return p.Base.Init()
 }
@@ -51,86 +144,255 @@ func (p *point24) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if b != p.Base {
 		return b, cmd
 	}
+	switch msg := msg.(type) {
+	case tickMsg:
+		return p, p.tick(msg)
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, *p.cardsKey):
+			p.cardsCnt = max(minCards, (p.cardsCnt+1)%(engine.MaxCards+1))
+			p.dealLevels()
+			p.setLever(p.CurrentLevel())
+			return p, cmd
+		case key.Matches(msg, *p.targetKey):
+			p.target = targets[(slices.Index(targets, p.target)+1)%len(targets)]
+			p.dealLevels()
+			p.setLever(p.CurrentLevel())
+			return p, cmd
+		case key.Matches(msg, *p.timedKey):
+			p.timed = !p.timed
+			p.setLever(p.CurrentLevel())
+			if p.timed {
+				p.ticker++
+				return p, tea.Batch(cmd, p.doTick())
+			}
+			return p, cmd
+		}
+		// the hand is over once given up, until it's reset or another level is picked
+		if p.timeUp || p.gaveUp {
+			if key.Matches(msg, *p.solutionKey) {
+				p.showSolution = true
+			}
+			return p, cmd
+		}
+		switch {
+		case key.Matches(msg, *p.undoKey):
+			p.undo()
+			return p, cmd
+		case key.Matches(msg, *p.giveUpKey):
+			p.giveUp()
+			return p, cmd
+		}
+	}
 	p.nums.Update(msg)
 	p.opers.Update(msg)
 	return p, cmd
 }
 
 func (p *point24) view() string {
-	keysView := lg.JoinHorizontal(lg.Center,
-		p.nums.View(),
-		"        ",
-		p.opers.View(),
-	)
-	if p.num == -1 {
-		return keysView
+	views := []string{
+		lg.JoinHorizontal(lg.Center,
+			p.nums.View(),
+			"        ",
+			p.opers.View(),
+		),
+		"",
 	}
-
-	resView := strconv.Itoa(p.num)
-	if p.oper != "" {
-		resView += p.oper
+	cards := p.engine.Cards()
+	for i, card := range cards {
+		if card == nil || card.Leaf() || i == p.picked {
+			continue
+		}
+		views = append(views, style.Help.Render(card.String()+" = "+card.Value()))
 	}
-	if p.num == 24 {
-		resView += "!"
+	switch {
+	case p.engine.Won():
+		res := p.engine.Result()
+		views = append(views, successStyle.Render(" "+res.String()+" = "+res.Value()+"! "))
+	case p.picked != -1:
+		card := cards[p.picked]
+		s := card.String()
+		if !card.Leaf() {
+			s += " = " + card.Value()
+		}
+		if p.oper != "" {
+			s += " " + p.oper
+		}
+		views = append(views, resStyle.Render(" "+s+" "))
 	}
-	switch len(resView) {
-	case 1:
-		resView = "  " + resView + "  "
-	case 2:
-		resView = " " + resView + "  "
-	default:
-		resView = " " + resView + " "
+	views = append(views, "", p.level.difficulty.String()+style.Help.Render(fmt.Sprintf("  target: %d", p.target)))
+	if p.timed {
+		left := max(0, time.Until(p.deadline).Round(time.Second))
+		views = append(views, style.Help.Render(fmt.Sprintf("time: %d:%02d  solved: %d  score: %d",
+			int(left.Minutes()), int(left.Seconds())%60, p.solved, p.score)))
+		if p.lastSkip != "" {
+			views = append(views, style.Help.Render("skipped: "+p.lastSkip))
+		}
 	}
-	if p.num == dest {
-		resView = successStyle.Render(resView)
-	} else {
-		resView = resStyle.Render(resView)
+	if p.showSolution {
+		solution := p.level.solutions[0]
+		views = append(views, style.Help.Render(fmt.Sprintf("solution: %s = %s, %s in total",
+			solution, solution.Value(), p.solutionsCount())))
 	}
-	return lg.JoinVertical(lg.Center,
-		keysView,
-		"",
-		resView,
-	)
+	return lg.JoinVertical(lg.Center, views...)
+}
+
+func (p *point24) helpInfo() string {
+	return fmt.Sprintf("Pick a number, an operator and another number to combine them into a new number.\n"+
+		"Our goal is to combine all the numbers into %d.\n"+
+		"In the timed mode, solve as many hands as you can in %s, harder hands score more.", p.target, roundDuration)
+}
+
+// dealLevels drops the dealt hands, the levels are dealt lazily as dealing more cards takes time
+func (p *point24) dealLevels() {
+	p.levels = make([]*level, len(p.difficulties))
 }
 
 func (p *point24) setLever(i int) {
-	p.nums = keyblock.NewKeysLine("a", "s", "d", "f")
-	p.nums.SetOnce(true)
-	p.nums.SetAction(p.numAction)
+	p.rd.Seed(p.LevelSeed())
+	if p.timed {
+		// a new round
+		p.deadline = time.Now().Add(roundDuration)
+		p.score = 0
+		p.solved = 0
+		p.timeUp = false
+		p.lastSkip = ""
+	}
+	if p.levels[i] == nil {
+		p.levels[i] = deal(p.rd, p.cardsCnt, p.target, p.difficulties[i])
+	}
+	p.deal(p.levels[i])
+}
+
+func (p *point24) deal(lvl *level) {
+	p.level = lvl
+	p.nums = keyblock.NewKeysLine(numKeys[:len(lvl.hand)]...)
+	for i := range p.nums {
+		p.nums.SetActionAt(i, func(*keyblock.Key) { p.numAction(i) })
+	}
 	p.opers = keyblock.NewKeysLine("h", "j", "k", "l")
-	p.opers.SetOnce(true)
-	p.opers.SetDisplays(plus, minus, times, divid)
+	p.opers.SetDisplays(engine.Plus, engine.Minus, engine.Times, engine.Divide)
 	p.opers.SetAction(p.operAction)
-	p.num = -1
-	level := p.levels[i]
-	for i, v := range level {
-		p.nums.SetDisplay(i, strconv.Itoa(v))
+	p.engine = engine.New(lvl.hand, p.target)
+	p.picked = -1
+	p.oper = ""
+	p.gaveUp = false
+	p.showSolution = false
+	p.solutionKey.SetEnabled(false)
+	p.refresh()
+}
+
+// dealRandom deals the next hand in the timed mode
+func (p *point24) dealRandom() {
+	p.deal(deal(p.rd, p.cardsCnt, p.target, difficulty(p.rd.Intn(int(hard)+1))))
+}
+
+func (p *point24) giveUp() {
+	if !p.timed {
+		// the solution shows on its key
+		p.gaveUp = true
+		p.solutionKey.SetEnabled(true)
+		return
 	}
+	solution := p.level.solutions[0]
+	p.lastSkip = solution.String() + " = " + solution.Value()
+	p.dealRandom()
+}
+
+func (p *point24) doTick() tea.Cmd {
+	ticker := p.ticker
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return tickMsg{ticker: ticker}
+	})
+}
+
+func (p *point24) tick(msg tickMsg) tea.Cmd {
+	if !p.timed || msg.ticker != p.ticker {
+		return nil
+	}
+	if !p.timeUp && !time.Now().Before(p.deadline) {
+		p.timeUp = true
+		// the round is over, not a hand solved
+		p.SkipRecord()
+		p.SetSuccess(fmt.Sprintf("Time's up! You solved %d hand(s) and scored %d.", p.solved, p.score))
+	}
+	return p.doTick()
 }
 
 func (p *point24) operAction(key *keyblock.Key) {
+	if p.picked == -1 {
+		p.SetError(errPickNumber)
+		return
+	}
 	p.oper = key.Display
+	p.refresh()
 }
 
-func (p *point24) numAction(key *keyblock.Key) {
-	num, err := strconv.Atoi(key.Display)
-	if err != nil {
-		p.SetError(err)
+func (p *point24) numAction(i int) {
+	switch {
+	case p.picked == i:
+		p.picked = -1
+		p.oper = ""
+	case p.picked == -1 || p.oper == "":
+		p.picked = i
+	default:
+		if err := p.engine.Apply(engine.Move{Left: p.picked, Right: i, Op: p.oper}); err != nil {
+			p.SetError(err)
+			return
+		}
+		p.picked = i
+		p.oper = ""
+		p.Emit(game.Moved)
+	}
+	p.refresh()
+	if !p.engine.Won() {
 		return
 	}
-	if p.oper == "" {
-		p.num = num
+	if p.timed {
+		p.solved++
+		p.score += int(p.level.difficulty) + 1
+		p.lastSkip = ""
+		p.dealRandom()
 		return
 	}
-	switch p.oper {
-	case plus:
-		p.num += num
-	case minus:
-		p.num -= num
-	case times:
-		p.num *= num
-	case divid:
-		p.num /= num
+	res := p.engine.Result()
+	p.SetSuccess(fmt.Sprintf("%s = %s, you found one of the %s solutions",
+		res, res.Value(), p.solutionsCount()))
+}
+
+// Moves is the operations done with the current hand, undone ones included
+func (p *point24) Moves() int {
+	return p.engine.Steps()
+}
+
+func (p *point24) solutionsCount() string {
+	n := len(p.level.solutions)
+	if n >= engine.MaxSolutions {
+		return "more than " + strconv.Itoa(n)
+	}
+	return strconv.Itoa(n)
+}
+
+func (p *point24) undo() {
+	if !p.engine.Undo() {
+		return
 	}
+	p.Emit(game.Undone)
+	p.picked = -1
 	p.oper = ""
+	p.refresh()
+}
+
+// refresh syncs the keys with the cards
+func (p *point24) refresh() {
+	for i, card := range p.engine.Cards() {
+		p.nums[i].SetPressed(card == nil)
+		if card != nil {
+			p.nums.SetDisplay(i, card.Value())
+		}
+	}
+	p.nums.SetActive(p.picked)
+	p.opers.SetActive(slices.IndexFunc(p.opers, func(k *keyblock.Key) bool {
+		return p.oper != "" && k.Display == p.oper
+	}))
 }
@@ -0,0 +1,131 @@
+package point24
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	engine "github.com/zrcoder/rdor/pkg/engine/point24"
+	"github.com/zrcoder/rdor/pkg/game"
+	"github.com/zrcoder/rdor/pkg/gametest"
+)
+
+var operKeys = map[string]string{engine.Plus: "h", engine.Minus: "j", engine.Times: "k", engine.Divide: "l"}
+
+// solve finds a solution of the current hand on another engine of the same hand
+func solve(t *testing.T, p *point24) []engine.Move {
+	t.Helper()
+	g := engine.New(p.level.hand, p.target)
+	var moves []engine.Move
+	var search func() bool
+	search = func() bool {
+		if g.Won() {
+			return true
+		}
+		for _, m := range g.Moves() {
+			if g.Apply(m) != nil {
+				continue
+			}
+			moves = append(moves, m)
+			if search() {
+				return true
+			}
+			moves = moves[:len(moves)-1]
+			g.Undo()
+		}
+		return false
+	}
+	if !search() {
+		t.Fatal("no solution")
+	}
+	return moves
+}
+
+// play presses the keys of the moves, after calling each with the index of the move
+func play(d *gametest.Driver, p *point24, moves []engine.Move, each func(i int)) {
+	for i, m := range moves {
+		if p.picked != m.Left {
+			d.Press(numKeys[m.Left])
+		}
+		d.Press(operKeys[m.Op], numKeys[m.Right])
+		each(i)
+	}
+}
+
+func TestSolve(t *testing.T) {
+	p := New().(*point24)
+	d := gametest.New(t, p).Snapshot("start")
+	play(d, p, solve(t, p), func(i int) {
+		if i == 0 {
+			d.Snapshot("first move")
+		}
+	})
+	d.Snapshot("solved").Golden()
+}
+
+func TestUndoAndGiveUp(t *testing.T) {
+	gametest.New(t, New()).
+		Press("h").Snapshot("operator first").
+		Press("a", "h", "s").Snapshot("combined").
+		Press("u").Snapshot("undone").
+		Press("g").Snapshot("gave up").
+		Press("v").Snapshot("solution").
+		Golden()
+}
+
+func TestNoSuccessAfterGiveUp(t *testing.T) {
+	p := NewDaily().(*point24)
+	succeeded := false
+	p.OnSuccess(func() { succeeded = true })
+	d := gametest.New(t, p)
+	moves := solve(t, p)
+	d.Press("g")
+	gaveUp := d.View()
+	play(d, p, moves, func(int) {})
+	if succeeded {
+		t.Error("the solution shown should not succeed")
+	}
+	if d.View() != gaveUp {
+		t.Errorf("the hand should not change after giving up, got\n%s", d.View())
+	}
+}
+
+func TestSeededDeal(t *testing.T) {
+	p := New().(*point24)
+	gametest.New(t, p).Press("n", "n")
+	q := New().(*point24)
+	gametest.New(t, q)
+	q.GoToLevel(2)
+	if !slices.Equal(p.level.hand, q.level.hand) {
+		t.Errorf("the hands of level 3 differ by the levels dealt before: %v, %v", p.level.hand, q.level.hand)
+	}
+}
+
+func TestTimeUp(t *testing.T) {
+	p := New().(*point24)
+	succeeded := false
+	p.OnEvent(func(e game.Event) {
+		if e.Kind == game.Succeeded {
+			succeeded = true
+		}
+	})
+	d := gametest.New(t, p).Press("m")
+	p.deadline = time.Now()
+	d.Send(tickMsg{ticker: p.ticker})
+	if !p.timeUp {
+		t.Fatal("the time should be up")
+	}
+	if succeeded {
+		t.Error("the time up should not succeed")
+	}
+}
+
+func TestFallbackHand(t *testing.T) {
+	for _, target := range targets {
+		for n := minCards; n <= engine.MaxCards; n++ {
+			if hand := fallbackHand(n, target); len(engine.Solve(hand, target)) == 0 {
+				t.Errorf("the fallback hand %v has no solution for %d", hand, target)
+			}
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package internal
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zrcoder/rdor/pkg/store"
+	"github.com/zrcoder/rdor/pkg/style"
+	"github.com/zrcoder/rdor/pkg/style/color"
+)
+
+const profilesKey = "profiles"
+
+// profiles are the names of the players sharing the machine, the latest one first
+type profiles struct {
+	Names []string `json:"names"`
+}
+
+var (
+	pickerUpKey    = key.NewBinding(key.WithKeys("up"), key.WithHelp("↑", "up"))
+	pickerDownKey  = key.NewBinding(key.WithKeys("down"), key.WithHelp("↓", "down"))
+	pickerEnterKey = key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "play"))
+	pickerQuitKey  = key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "quit"))
+)
+
+// picker picks the player at launch, or adds a new one, then goes to the launcher
+type picker struct {
+	store    store.Store
+	profiles profiles
+	cursor   int // the row of the input to add a player if it's len(profiles.Names)
+	input    textinput.Model
+	help     help.Model
+	launcher *rdor
+	err      error
+}
+
+func newPicker(st store.Store, launcher *rdor) *picker {
+	p := &picker{store: st, launcher: launcher, input: textinput.New(), help: help.New()}
+	p.input.Prompt = "new player: "
+	p.input.CharLimit = 12
+	if err := st.Load(profilesKey, &p.profiles); err != nil {
+		p.err = err
+	}
+	p.focus()
+	return p
+}
+
+func (p *picker) Init() tea.Cmd { return nil }
+
+func (p *picker) Update(m tea.Msg) (tea.Model, tea.Cmd) {
+	msg, ok := m.(tea.KeyMsg)
+	if !ok {
+		return p, nil
+	}
+	p.err = nil
+	switch {
+	case key.Matches(msg, pickerQuitKey):
+		return p, tea.Quit
+	case key.Matches(msg, pickerUpKey):
+		p.cursor = (p.cursor + len(p.profiles.Names)) % (len(p.profiles.Names) + 1)
+		p.focus()
+	case key.Matches(msg, pickerDownKey):
+		p.cursor = (p.cursor + 1) % (len(p.profiles.Names) + 1)
+		p.focus()
+	case key.Matches(msg, pickerEnterKey):
+		return p.enter()
+	default:
+		var cmd tea.Cmd
+		p.input, cmd = p.input.Update(msg)
+		return p, cmd
+	}
+	return p, nil
+}
+
+// enter plays as the player under the cursor, or the new one typed
+func (p *picker) enter() (tea.Model, tea.Cmd) {
+	name := strings.TrimSpace(p.input.Value())
+	if p.cursor < len(p.profiles.Names) {
+		name = p.profiles.Names[p.cursor]
+	}
+	if name == "" {
+		return p, nil
+	}
+	if err := p.pick(name); err != nil {
+		p.err = err
+		return p, nil
+	}
+	p.input.Reset()
+	p.launcher.setPlayer(name)
+	return p.launcher, nil
+}
+
+// focus lets the input take the keys only if the cursor is on it
+func (p *picker) focus() {
+	if p.cursor == len(p.profiles.Names) {
+		p.input.Focus()
+	} else {
+		p.input.Blur()
+	}
+}
+
+// pick moves name to the first and saves the profiles
+func (p *picker) pick(name string) error {
+	names := slices.DeleteFunc(p.profiles.Names, func(s string) bool { return s == name })
+	p.profiles.Names = append([]string{name}, names...)
+	p.cursor = 0
+	p.focus()
+	return p.store.Save(profilesKey, p.profiles)
+}
+
+func (p *picker) View() string {
+	selected := lipgloss.NewStyle().Foreground(color.Orange)
+	rows := make([]string, 0, len(p.profiles.Names)+1)
+	for i, name := range append(slices.Clone(p.profiles.Names), p.input.View()) {
+		if i == p.cursor {
+			rows = append(rows, selected.Render("> "+name))
+		} else {
+			rows = append(rows, "  "+name)
+		}
+	}
+	views := []string{style.Title.Render("Who's playing?"), "", strings.Join(rows, "\n"), ""}
+	if p.err != nil {
+		views = append(views, style.Error.Render(p.err.Error()), "")
+	}
+	views = append(views, p.help.ShortHelpView([]key.Binding{pickerUpKey, pickerDownKey, pickerEnterKey, pickerQuitKey}))
+	return lipgloss.NewStyle().Padding(1, 2).Render(lipgloss.JoinVertical(lipgloss.Left, views...))
+}
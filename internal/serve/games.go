@@ -0,0 +1,168 @@
+package serve
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	hanoigame "github.com/zrcoder/rdor/internal/hanoi"
+	"github.com/zrcoder/rdor/internal/maze/levels"
+	"github.com/zrcoder/rdor/pkg/engine/hanoi"
+	"github.com/zrcoder/rdor/pkg/engine/maze"
+	"github.com/zrcoder/rdor/pkg/engine/sokoban"
+	"github.com/zrcoder/rdor/pkg/grid"
+)
+
+// level is the engine of a level with the moves written as strings
+type level interface {
+	Moves() []string
+	Apply(move string) error
+	Won() bool
+	Steps() int
+	Board() []string
+}
+
+// game is a game to serve, load returns the level i counting from 0
+type game struct {
+	levels int
+	load   func(i int) (level, error)
+}
+
+var games = map[string]*game{
+	"sokoban": {levels: sokoban.Levels, load: loadSokoban},
+	"maze":    {levels: len(levels.Names), load: loadMaze},
+	"hanoi":   {levels: len(hanoigame.Levels), load: loadHanoi},
+}
+
+// Games returns the names of the games can be served
+func Games() []string {
+	res := make([]string, 0, len(games))
+	for name := range games {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+var directions = map[string]grid.Direction{"up": grid.Up, "down": grid.Down, "left": grid.Left, "right": grid.Right}
+
+func directionName(d grid.Direction) string {
+	for name, dir := range directions {
+		if dir == d {
+			return name
+		}
+	}
+	return ""
+}
+
+func parseDirection(move string) (grid.Direction, error) {
+	d, ok := directions[move]
+	if !ok {
+		return d, fmt.Errorf("unknown move %q, should be up, down, left or right", move)
+	}
+	return d, nil
+}
+
+func directionNames(ds []grid.Direction) []string {
+	res := make([]string, len(ds))
+	for i, d := range ds {
+		res[i] = directionName(d)
+	}
+	return res
+}
+
+func gridLines(g *grid.Grid[rune]) []string {
+	var res []string
+	g.RangeRows(func(_ int, row []rune, _ bool) (end bool) {
+		res = append(res, string(row))
+		return
+	})
+	return res
+}
+
+type sokobanLevel struct{ *sokoban.Game }
+
+func loadSokoban(i int) (level, error) {
+	g, err := sokoban.Level(i)
+	if err != nil {
+		return nil, err
+	}
+	return sokobanLevel{g}, nil
+}
+
+func (l sokobanLevel) Moves() []string { return directionNames(l.Game.Moves()) }
+func (l sokobanLevel) Board() []string { return gridLines(l.Grid()) }
+
+func (l sokobanLevel) Apply(move string) error {
+	d, err := parseDirection(move)
+	if err != nil {
+		return err
+	}
+	return l.Game.Apply(d)
+}
+
+type mazeLevel struct{ *maze.Game }
+
+func loadMaze(i int) (level, error) {
+	s, err := levels.ReadLevel(levels.Names[i])
+	if err != nil {
+		return nil, err
+	}
+	g, err := maze.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	return mazeLevel{g}, nil
+}
+
+func (l mazeLevel) Moves() []string { return directionNames(l.Game.Moves()) }
+func (l mazeLevel) Board() []string { return gridLines(l.Grid()) }
+
+func (l mazeLevel) Apply(move string) error {
+	d, err := parseDirection(move)
+	if err != nil {
+		return err
+	}
+	return l.Game.Apply(d)
+}
+
+type hanoiLevel struct{ *hanoi.Game }
+
+// the levels of hanoi have the disks of the levels in the terminal
+func loadHanoi(i int) (level, error) {
+	return hanoiLevel{hanoi.New(hanoigame.Levels[i])}, nil
+}
+
+// a move of hanoi is written as "1-3", moving the top disk of pile 1 onto pile 3
+func (l hanoiLevel) Moves() []string {
+	moves := l.Game.Moves()
+	res := make([]string, len(moves))
+	for i, m := range moves {
+		res[i] = fmt.Sprintf("%d-%d", m.From+1, m.To+1)
+	}
+	return res
+}
+
+func (l hanoiLevel) Apply(move string) error {
+	from, to, ok := strings.Cut(move, "-")
+	f, err1 := strconv.Atoi(from)
+	t, err2 := strconv.Atoi(to)
+	if !ok || err1 != nil || err2 != nil || f < 1 || f > hanoi.Piles || t < 1 || t > hanoi.Piles {
+		return fmt.Errorf("unknown move %q, should be like 1-3", move)
+	}
+	return l.Game.Apply(hanoi.Move{From: f - 1, To: t - 1})
+}
+
+// Board lists the disks of every pile from the bottom, 1 is the smallest
+func (l hanoiLevel) Board() []string {
+	res := make([]string, hanoi.Piles)
+	for i := range res {
+		disks := make([]string, 0, l.Disks())
+		for _, d := range l.Pile(i) {
+			disks = append(disks, strconv.Itoa(d))
+		}
+		res[i] = strings.Join(disks, " ")
+	}
+	return res
+}
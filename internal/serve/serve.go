@@ -0,0 +1,198 @@
+// Package serve plays a game with JSON lines, the commands are read from the input,
+// and the events are written to the output, so agents in any language can play the games.
+//
+// The commands:
+//
+//	{"cmd": "new", "level": 1}      start the level, counting from 1, the next level if omitted
+//	{"cmd": "move", "move": "up"}   make a move, the legal moves are listed in the states
+//	{"cmd": "undo"}                 take back the last move
+//	{"cmd": "state"}                query the state
+//	{"cmd": "quit"}                 stop serving
+//
+// The events:
+//
+//	{"event": "ready", "game": "sokoban", "levels": 51}
+//	{"event": "state", "level": 1, "board": [...], "moves": [...], "steps": 0, "won": false}
+//	{"event": "won", "level": 1, "steps": 33}
+//	{"event": "error", "error": "illegal move"}
+package serve
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type request struct {
+	Cmd   string `json:"cmd"`
+	Level int    `json:"level"`
+	Move  string `json:"move"`
+}
+
+type readyEvent struct {
+	Event  string `json:"event"`
+	Game   string `json:"game"`
+	Levels int    `json:"levels"`
+}
+
+type stateEvent struct {
+	Event string   `json:"event"`
+	Level int      `json:"level"`
+	Board []string `json:"board"`
+	Moves []string `json:"moves"`
+	Steps int      `json:"steps"`
+	Won   bool     `json:"won"`
+}
+
+type wonEvent struct {
+	Event string `json:"event"`
+	Level int    `json:"level"`
+	Steps int    `json:"steps"`
+}
+
+type errorEvent struct {
+	Event string `json:"event"`
+	Error string `json:"error"`
+}
+
+var errNoLevel = errors.New("no level started, send the new command first")
+
+type server struct {
+	game  *game
+	enc   *json.Encoder
+	index int // the current level counting from 1, 0 before any level
+	level level
+	// history is the moves made in the current level, undoing replays all but the last
+	history []string
+}
+
+// Serve plays the game named name with the commands read from r, and writes the events to w,
+// it returns when r ends or the quit command comes
+func Serve(name string, r io.Reader, w io.Writer) error {
+	g, ok := games[name]
+	if !ok {
+		return fmt.Errorf("unknown game %q, should be one of %s", name, strings.Join(Games(), ", "))
+	}
+	s := &server{game: g, enc: json.NewEncoder(w)}
+	if err := s.enc.Encode(readyEvent{Event: "ready", Game: name, Levels: g.levels}); err != nil {
+		return err
+	}
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		req := &request{}
+		if err := json.Unmarshal(line, req); err != nil {
+			if err := s.fail(err); err != nil {
+				return err
+			}
+			continue
+		}
+		if req.Cmd == "quit" {
+			return nil
+		}
+		if err := s.handle(req); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+// handle runs the command, the errors of the command are written as events,
+// only the errors of writing are returned
+func (s *server) handle(req *request) error {
+	switch req.Cmd {
+	case "new":
+		i := req.Level
+		if i == 0 {
+			i = s.index%s.game.levels + 1
+		}
+		if i < 1 || i > s.game.levels {
+			return s.fail(fmt.Errorf("the levels must between 1 and %d", s.game.levels))
+		}
+		if err := s.load(i); err != nil {
+			return s.fail(err)
+		}
+	case "move":
+		if s.level == nil {
+			return s.fail(errNoLevel)
+		}
+		if err := s.level.Apply(req.Move); err != nil {
+			return s.fail(err)
+		}
+		s.history = append(s.history, req.Move)
+		if s.level.Won() {
+			if err := s.state(); err != nil {
+				return err
+			}
+			return s.enc.Encode(wonEvent{Event: "won", Level: s.index, Steps: s.level.Steps()})
+		}
+	case "undo":
+		if s.level == nil {
+			return s.fail(errNoLevel)
+		}
+		if len(s.history) == 0 {
+			return s.fail(errors.New("nothing to undo"))
+		}
+		if err := s.replay(s.history[:len(s.history)-1]); err != nil {
+			return s.fail(err)
+		}
+	case "state":
+		if s.level == nil {
+			return s.fail(errNoLevel)
+		}
+	default:
+		return s.fail(fmt.Errorf("unknown command %q", req.Cmd))
+	}
+	return s.state()
+}
+
+func (s *server) load(i int) error {
+	lvl, err := s.game.load(i - 1)
+	if err != nil {
+		return err
+	}
+	s.index = i
+	s.level = lvl
+	s.history = nil
+	return nil
+}
+
+// replay restarts the level and makes the moves again
+func (s *server) replay(moves []string) error {
+	if err := s.load(s.index); err != nil {
+		return err
+	}
+	for _, m := range moves {
+		if err := s.level.Apply(m); err != nil {
+			return err
+		}
+		s.history = append(s.history, m)
+	}
+	return nil
+}
+
+func (s *server) state() error {
+	moves := s.level.Moves()
+	if moves == nil {
+		moves = []string{}
+	}
+	return s.enc.Encode(stateEvent{
+		Event: "state",
+		Level: s.index,
+		Board: s.level.Board(),
+		Moves: moves,
+		Steps: s.level.Steps(),
+		Won:   s.level.Won(),
+	})
+}
+
+func (s *server) fail(err error) error {
+	return s.enc.Encode(errorEvent{Event: "error", Error: err.Error()})
+}
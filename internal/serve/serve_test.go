@@ -0,0 +1,210 @@
+package serve
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/zrcoder/rdor/internal/maze/levels"
+	"github.com/zrcoder/rdor/pkg/engine/maze"
+)
+
+func TestServe(t *testing.T) {
+	in := strings.Join([]string{
+		`{"cmd": "state"}`,
+		`{"cmd": "new", "level": 1}`,
+		`{"cmd": "move", "move": "1-3"}`,
+		`{"cmd": "undo"}`,
+		`{"cmd": "move", "move": "3-1"}`,
+		`{"cmd": "move", "move": "1-3"}`,
+		`{"cmd": "move", "move": "1-2"}`,
+		`{"cmd": "move", "move": "3-2"}`,
+		`{"cmd": "move", "move": "1-3"}`,
+		`{"cmd": "move", "move": "2-1"}`,
+		`{"cmd": "move", "move": "2-3"}`,
+		`{"cmd": "move", "move": "1-3"}`,
+		`{"cmd": "quit"}`,
+		`{"cmd": "state"}`,
+	}, "\n")
+	// the levels are the ones in the terminal, the first has 3 disks
+	want := []string{
+		`{"event":"ready","game":"hanoi","levels":5}`,
+		`{"event":"error","error":"no level started, send the new command first"}`,
+		`{"event":"state","level":1,"board":["3 2 1","",""],"moves":["1-2","1-3"],"steps":0,"won":false}`,
+		`{"event":"state","level":1,"board":["3 2","","1"],"moves":["1-2","3-1","3-2"],"steps":1,"won":false}`,
+		`{"event":"state","level":1,"board":["3 2 1","",""],"moves":["1-2","1-3"],"steps":0,"won":false}`,
+		`{"event":"error","error":"illegal move"}`,
+		`{"event":"state","level":1,"board":["3 2","","1"],"moves":["1-2","3-1","3-2"],"steps":1,"won":false}`,
+		`{"event":"state","level":1,"board":["3","2","1"],"moves":["2-1","3-1","3-2"],"steps":2,"won":false}`,
+		`{"event":"state","level":1,"board":["3","2 1",""],"moves":["1-3","2-1","2-3"],"steps":3,"won":false}`,
+		`{"event":"state","level":1,"board":["","2 1","3"],"moves":["2-1","2-3","3-1"],"steps":4,"won":false}`,
+		`{"event":"state","level":1,"board":["1","2","3"],"moves":["1-2","1-3","2-3"],"steps":5,"won":false}`,
+		`{"event":"state","level":1,"board":["1","","3 2"],"moves":["1-2","1-3","3-2"],"steps":6,"won":false}`,
+		`{"event":"state","level":1,"board":["","","3 2 1"],"moves":["3-1","3-2"],"steps":7,"won":true}`,
+		`{"event":"won","level":1,"steps":7}`,
+	}
+	out := &bytes.Buffer{}
+	if err := Serve("hanoi", strings.NewReader(in), out); err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(got) != len(want) {
+		t.Fatalf("got %d events, want %d:\n%s", len(got), len(want), out)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d:\nwant %s\ngot  %s", i, want[i], got[i])
+		}
+	}
+}
+
+func TestUnknownGame(t *testing.T) {
+	if err := Serve("chess", strings.NewReader(""), &bytes.Buffer{}); err == nil {
+		t.Fatal("serving an unknown game should fail")
+	}
+}
+
+// event has the fields of all the events
+type event struct {
+	Event string   `json:"event"`
+	Error string   `json:"error"`
+	Board []string `json:"board"`
+	Moves []string `json:"moves"`
+	Steps int      `json:"steps"`
+	Won   bool     `json:"won"`
+}
+
+// play starts the first level of the game, makes the moves and returns the events after the ready one
+func play(t *testing.T, name string, moves ...string) []event {
+	t.Helper()
+	in := &strings.Builder{}
+	in.WriteString(`{"cmd": "new", "level": 1}` + "\n")
+	for _, m := range moves {
+		fmt.Fprintf(in, `{"cmd": "move", "move": %q}`+"\n", m)
+	}
+	out := &bytes.Buffer{}
+	if err := Serve(name, strings.NewReader(in.String()), out); err != nil {
+		t.Fatal(err)
+	}
+	var res []event
+	dec := json.NewDecoder(out)
+	for dec.More() {
+		var e event
+		if err := dec.Decode(&e); err != nil {
+			t.Fatal(err)
+		}
+		res = append(res, e)
+	}
+	return res[1:]
+}
+
+// checkPlay checks the events of a play with an illegal move after the start, then the legal ones to win
+func checkPlay(t *testing.T, events []event, illegal, wins []string) {
+	t.Helper()
+	// the start, the illegal move, every move and the won event
+	if len(events) != len(wins)+3 {
+		t.Fatalf("got %d events, want %d", len(events), len(wins)+3)
+	}
+	start := events[0]
+	if start.Event != "state" || start.Steps != 0 || start.Won {
+		t.Fatalf("got %+v, want the state of the start", start)
+	}
+	if e := events[1]; e.Event != "error" || e.Error != "illegal move" {
+		t.Errorf("moving %s at the start got %+v, want the illegal move error", illegal[0], e)
+	}
+	for _, m := range illegal {
+		if slices.Contains(start.Moves, m) {
+			t.Errorf("the illegal move %s should not be listed in %v", m, start.Moves)
+		}
+	}
+	first := events[2]
+	if first.Event != "state" || first.Steps != 1 || strings.Join(first.Board, "\n") == strings.Join(start.Board, "\n") {
+		t.Errorf("the legal move %s got %+v, want the board changed in one step", wins[0], first)
+	}
+	for i, e := range events[2 : len(events)-1] {
+		if e.Event != "state" || e.Steps != i+1 || e.Won != (i == len(wins)-1) {
+			t.Errorf("move %d %s got %+v", i+1, wins[i], e)
+		}
+	}
+	if won := events[len(events)-1]; won.Event != "won" || won.Steps != len(wins) {
+		t.Errorf("got %+v, want won in %d steps", won, len(wins))
+	}
+}
+
+func TestSokoban(t *testing.T) {
+	// push the left box right into its slot, then walk around and push the right box left
+	wins := []string{"left", "down", "right", "up", "right", "right", "right", "right", "down", "left"}
+	events := play(t, "sokoban", append([]string{"up"}, wins...)...)
+	checkPlay(t, events, []string{"up"}, wins)
+}
+
+func TestMaze(t *testing.T) {
+	s, err := levels.ReadLevel(levels.Names[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	illegal, wins := explore(strings.Split(strings.TrimRight(s, "\n"), "\n"))
+	events := play(t, "maze", append(illegal[:1], wins...)...)
+	checkPlay(t, events, illegal, wins)
+}
+
+// explore walks through the maze depth first till all the goals are taken,
+// it returns the moves blocked by the walls at the start and the moves of the walk
+func explore(lines []string) (illegal, walk []string) {
+	at := func(r, c int) byte {
+		if r < 0 || r >= len(lines) || c < 0 || c >= len(lines[r]) {
+			return maze.HorizontalWall
+		}
+		return lines[r][c]
+	}
+	// the cells are 2 rows or 4 columns apart, with the walls between
+	steps := []struct {
+		name, back string
+		dr, dc     int
+	}{{"up", "down", -1, 0}, {"down", "up", 1, 0}, {"left", "right", 0, -2}, {"right", "left", 0, 2}}
+	open := func(r, c, dr, dc int) bool {
+		w := at(r+dr, c+dc)
+		return w != maze.VerticalWall && w != maze.HorizontalWall && at(r+2*dr, c+2*dc) != maze.HorizontalWall
+	}
+	var start [2]int
+	goals := 0
+	for r, line := range lines {
+		for c := range line {
+			switch line[c] {
+			case maze.Player:
+				start = [2]int{r, c}
+			case maze.Goal:
+				goals++
+			}
+		}
+	}
+	for _, s := range steps {
+		if !open(start[0], start[1], s.dr, s.dc) {
+			illegal = append(illegal, s.name)
+		}
+	}
+	seen := map[[2]int]bool{}
+	var dfs func(r, c int)
+	dfs = func(r, c int) {
+		seen[[2]int{r, c}] = true
+		if at(r, c) == maze.Goal {
+			goals--
+		}
+		for _, s := range steps {
+			next := [2]int{r + 2*s.dr, c + 2*s.dc}
+			if goals == 0 || seen[next] || !open(r, c, s.dr, s.dc) {
+				continue
+			}
+			walk = append(walk, s.name)
+			dfs(next[0], next[1])
+			if goals > 0 {
+				walk = append(walk, s.back)
+			}
+		}
+	}
+	dfs(start[0], start[1])
+	return illegal, walk
+}
@@ -0,0 +1,16 @@
+package sokoban
+
+import (
+	"testing"
+
+	"github.com/zrcoder/rdor/pkg/gametest"
+)
+
+func TestMoves(t *testing.T) {
+	gametest.New(t, New()).Snapshot("start").
+		Press("up", "left", "left", "down", "right").Snapshot("moved").
+		Press("u", "u").Snapshot("undone").
+		Press("r").Snapshot("reset").
+		Press("n").Snapshot("next level").
+		Golden()
+}
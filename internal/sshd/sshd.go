@@ -0,0 +1,138 @@
+// Package sshd hosts rdor over ssh, every connection plays in a program of its own,
+// and the data of the players are kept apart by their public keys.
+package sshd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	"github.com/charmbracelet/wish/activeterm"
+	"github.com/charmbracelet/wish/bubbletea"
+	"github.com/charmbracelet/wish/logging"
+	"github.com/muesli/termenv"
+	"github.com/zrcoder/rdor/internal"
+	"github.com/zrcoder/rdor/pkg/leaderboard"
+	"github.com/zrcoder/rdor/pkg/store"
+)
+
+// Options are the options of the server
+type Options struct {
+	// Addr is the address to listen on, e.g. :2222
+	Addr string
+	// Dir keeps the host key and the data of the players, ssh in the home of the store if empty
+	Dir string
+}
+
+// Run serves until it's interrupted
+func Run(opts Options) error {
+	s, err := newServer(opts)
+	if err != nil {
+		return err
+	}
+	l, err := net.Listen("tcp", opts.Addr)
+	if err != nil {
+		return err
+	}
+	done := make(chan os.Signal, 1)
+	signal.Notify(done, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-done
+		s.Close()
+	}()
+	log.Printf("serving rdor over ssh on %s, play with: ssh -p %d localhost", l.Addr(), l.Addr().(*net.TCPAddr).Port)
+	if err := s.Serve(l); err != nil && !errors.Is(err, ssh.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func newServer(opts Options) (*ssh.Server, error) {
+	if opts.Dir == "" {
+		home, err := store.Home()
+		if err != nil {
+			return nil, err
+		}
+		opts.Dir = filepath.Join(home, "ssh")
+	}
+	// the styles of the games are shared by all the sessions,
+	// so they are rendered with the colors most terminals support instead of detecting every client's
+	lipgloss.SetColorProfile(termenv.ANSI256)
+	lipgloss.SetHasDarkBackground(true)
+	names := &names{store: store.Store{Dir: opts.Dir}}
+	return wish.NewServer(
+		wish.WithAddress(opts.Addr),
+		wish.WithHostKeyPath(filepath.Join(opts.Dir, "ssh_host_ed25519")),
+		// any key is welcome, but a user name belongs to the first key logged in with it,
+		// so nobody records in the shared leaderboards under the name of another
+		wish.WithPublicKeyAuth(func(ctx ssh.Context, key ssh.PublicKey) bool {
+			ok, err := names.bind(ctx.User(), key)
+			if err != nil {
+				log.Printf("binding %s: %v", ctx.User(), err)
+			}
+			return ok
+		}),
+		wish.WithMiddleware(
+			bubbletea.Middleware(handler(opts.Dir)),
+			activeterm.Middleware(),
+			logging.Middleware(),
+		),
+	)
+}
+
+func handler(dir string) bubbletea.Handler {
+	return func(s ssh.Session) (tea.Model, []tea.ProgramOption) {
+		opts := internal.Options{
+			Store: store.Store{Dir: playerDir(dir, s.PublicKey())},
+			// the leaderboards are shared by everyone on the server
+			Board:  leaderboard.Board{Store: store.Store{Dir: dir}},
+			Player: s.User(),
+		}
+		return internal.New(opts), []tea.ProgramOption{tea.WithAltScreen()}
+	}
+}
+
+// playerDir is where the data of the player with key is kept
+func playerDir(dir string, key ssh.PublicKey) string {
+	return filepath.Join(dir, "players", fingerprint(key))
+}
+
+func fingerprint(key ssh.PublicKey) string {
+	sum := sha256.Sum256(key.Marshal())
+	return hex.EncodeToString(sum[:])
+}
+
+const namesKey = "names"
+
+// names binds the user names to the keys first logged in with them
+type names struct {
+	store store.Store
+	mu    sync.Mutex
+}
+
+// bind binds name to key if it's not bound yet, and reports whether name belongs to key
+func (n *names) bind(name string, key ssh.PublicKey) (bool, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	keys := map[string]string{}
+	if err := n.store.Load(namesKey, &keys); err != nil {
+		return false, err
+	}
+	fp := fingerprint(key)
+	if bound, ok := keys[name]; ok {
+		return bound == fp, nil
+	}
+	keys[name] = fp
+	return true, n.store.Save(namesKey, keys)
+}
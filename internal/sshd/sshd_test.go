@@ -0,0 +1,160 @@
+package sshd
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zrcoder/rdor/pkg/store"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+func newKey(t *testing.T) gossh.Signer {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := gossh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+func TestPlayerDir(t *testing.T) {
+	a, b := newKey(t).PublicKey(), newKey(t).PublicKey()
+	if playerDir("d", a) != playerDir("d", a) {
+		t.Error("the same key should have the same directory")
+	}
+	if playerDir("d", a) == playerDir("d", b) {
+		t.Error("different keys should have different directories")
+	}
+}
+
+// session logs in as user and returns the input of the terminal,
+// and waits for the output to show want, the output seen so far is returned
+func session(t *testing.T, user string) (io.Writer, func(want string) string) {
+	t.Helper()
+	s, err := newServer(Options{Addr: "127.0.0.1:0", Dir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go s.Serve(l)
+	t.Cleanup(func() { s.Close() })
+
+	client, err := gossh.Dial("tcp", l.Addr().String(), &gossh.ClientConfig{
+		User:            user,
+		Auth:            []gossh.AuthMethod{gossh.PublicKeys(newKey(t))},
+		HostKeyCallback: gossh.InsecureIgnoreHostKey(),
+		Timeout:         5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	sess, err := client.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sess.Close() })
+	if err := sess.RequestPty("xterm-256color", 40, 100, gossh.TerminalModes{}); err != nil {
+		t.Fatal(err)
+	}
+	in, err := sess.StdinPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := sess.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sess.Shell(); err != nil {
+		t.Fatal(err)
+	}
+
+	chunks := make(chan string)
+	go func() {
+		defer close(chunks)
+		buf := make([]byte, 1024)
+		for {
+			n, err := out.Read(buf)
+			if n > 0 {
+				chunks <- string(buf[:n])
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	var seen strings.Builder
+	wait := func(want string) string {
+		t.Helper()
+		timeout := time.After(10 * time.Second)
+		for !strings.Contains(seen.String(), want) {
+			select {
+			case chunk, ok := <-chunks:
+				if !ok {
+					t.Fatalf("the session ended before %q showed", want)
+				}
+				seen.WriteString(chunk)
+			case <-timeout:
+				t.Fatalf("%q didn't show in time: %q", want, seen.String())
+			}
+		}
+		return seen.String()
+	}
+	return in, wait
+}
+
+func TestSession(t *testing.T) {
+	_, wait := session(t, "player")
+	wait("Welcome to rdor, player")
+}
+
+func TestSwitchPlayer(t *testing.T) {
+	in, wait := session(t, "player")
+	wait("Welcome to rdor, player")
+	// the player bound to the key can't switch to another name, so the keys go to the launcher,
+	// and enter starts the first game, which shows its seed
+	io.WriteString(in, "\x10")
+	io.WriteString(in, "mallory\r")
+	seen := wait("seed ")
+	if strings.Contains(seen, "Who's playing?") || strings.Contains(seen, "mallory") {
+		t.Error("the player over ssh should not pick another name")
+	}
+	if strings.Contains(seen, "switch player") {
+		t.Error("the key to switch the player should not be shown over ssh")
+	}
+}
+
+func TestNames(t *testing.T) {
+	n := &names{store: store.Store{Dir: t.TempDir()}}
+	a, b := newKey(t).PublicKey(), newKey(t).PublicKey()
+	for _, c := range []struct {
+		name string
+		key  gossh.PublicKey
+		want bool
+	}{
+		{"alice", a, true},
+		{"alice", a, true},
+		{"alice", b, false},
+		{"bob", b, true},
+	} {
+		got, err := n.bind(c.name, c.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.want {
+			t.Errorf("binding %s got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
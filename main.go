@@ -1,17 +1,64 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/zrcoder/rdor/internal"
+	"github.com/zrcoder/rdor/internal/serve"
+	"github.com/zrcoder/rdor/internal/sshd"
 )
 
 //go:generate go run ./internal/gen_tools
+//go:generate go run ./internal/gen_tools -lint
 
 func main() {
-	if err := internal.Run(); err != nil {
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		runServe(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "ssh" {
+		runSSH(os.Args[2:])
+		return
+	}
+	var opts internal.Options
+	flag.StringVar(&opts.Host, "host", "", "host a game of Last over network on the address, e.g. :7777")
+	flag.StringVar(&opts.Join, "join", "", "join a game of Last hosted on the address, e.g. 192.168.1.2:7777")
+	flag.StringVar(&opts.Player, "player", "", "the name of the player, picked at launch if empty")
+	flag.Int64Var(&opts.Seed, "seed", 0, "the seed of the random boards, the same seed replays the same boards")
+	flag.Parse()
+	if opts.Host != "" && opts.Join != "" {
+		fmt.Println("can not host and join at the same time")
+		os.Exit(1)
+	}
+	if err := internal.Run(opts); err != nil {
 		fmt.Println("Error running program:", err)
 		os.Exit(1)
 	}
 }
+
+// runServe plays a game with JSON lines over stdin and stdout, see package serve for the protocol
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	name := fs.String("game", "sokoban", "the game to serve, one of "+strings.Join(serve.Games(), ", "))
+	fs.Parse(args)
+	if err := serve.Serve(*name, os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "Error serving:", err)
+		os.Exit(1)
+	}
+}
+
+// runSSH hosts the launcher over ssh, see package sshd
+func runSSH(args []string) {
+	fs := flag.NewFlagSet("ssh", flag.ExitOnError)
+	var opts sshd.Options
+	fs.StringVar(&opts.Addr, "addr", ":2222", "the address to listen on")
+	fs.StringVar(&opts.Dir, "dir", "", "the directory keeping the host key and the data of the players, ssh in the rdor home by default")
+	fs.Parse(args)
+	if err := sshd.Run(opts); err != nil {
+		fmt.Fprintln(os.Stderr, "Error serving over ssh:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,110 @@
+// Package achievement tracks the achievements of the players across the games,
+// evaluated on the events of the games, with the progress kept in a store.
+package achievement
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/zrcoder/rdor/pkg/game"
+	"github.com/zrcoder/rdor/pkg/store"
+)
+
+const storeKey = "achievements"
+
+// Achievement is a goal across the games, unlocked when Goal marks are made
+type Achievement struct {
+	ID   string
+	Name string
+	Desc string
+	Goal int
+	// Mark returns the mark made by the event, like the level solved, empty if none,
+	// the same marks count once
+	Mark func(e game.Event) string
+}
+
+// progress of all the players, by the player and then the id of the achievement
+type progress map[string]map[string][]string
+
+// the sessions hosted over ssh may save at the same time
+var mu sync.Mutex
+
+// Tracker tracks the achievements of a player
+type Tracker struct {
+	store        store.Store
+	player       string
+	achievements []*Achievement
+	marks        map[string][]string
+	unlocked     []*Achievement // unlocked but not taken yet
+}
+
+// NewTracker tracks the achievements for the player, with the progress saved in st
+func NewTracker(st store.Store, player string, achievements []*Achievement) (*Tracker, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	all := progress{}
+	if err := st.Load(storeKey, &all); err != nil {
+		return nil, err
+	}
+	marks := all[player]
+	if marks == nil {
+		marks = map[string][]string{}
+	}
+	return &Tracker{store: st, player: player, achievements: achievements, marks: marks}, nil
+}
+
+// Achievements are all the achievements tracked
+func (t *Tracker) Achievements() []*Achievement {
+	return t.achievements
+}
+
+// Progress is how many marks made for a
+func (t *Tracker) Progress(a *Achievement) int {
+	return min(len(t.marks[a.ID]), a.Goal)
+}
+
+// Done reports whether a is unlocked
+func (t *Tracker) Done(a *Achievement) bool {
+	return t.Progress(a) == a.Goal
+}
+
+// Track evaluates all the achievements on e, and saves the progress if any made
+func (t *Tracker) Track(e game.Event) error {
+	changed := false
+	for _, a := range t.achievements {
+		if t.Done(a) {
+			continue
+		}
+		m := a.Mark(e)
+		if m == "" || slices.Contains(t.marks[a.ID], m) {
+			continue
+		}
+		t.marks[a.ID] = append(t.marks[a.ID], m)
+		changed = true
+		if t.Done(a) {
+			t.unlocked = append(t.unlocked, a)
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return t.save()
+}
+
+// Unlocked takes the achievements unlocked since the last call
+func (t *Tracker) Unlocked() []*Achievement {
+	res := t.unlocked
+	t.unlocked = nil
+	return res
+}
+
+func (t *Tracker) save() error {
+	mu.Lock()
+	defer mu.Unlock()
+	all := progress{}
+	if err := t.store.Load(storeKey, &all); err != nil {
+		return err
+	}
+	all[t.player] = t.marks
+	return t.store.Save(storeKey, all)
+}
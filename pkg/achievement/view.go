@@ -0,0 +1,87 @@
+package achievement
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zrcoder/rdor/pkg/style"
+	"github.com/zrcoder/rdor/pkg/style/color"
+)
+
+// Key shows the gallery of the achievements in the launcher
+var Key = key.NewBinding(key.WithKeys("ctrl+a"), key.WithHelp("ctrl+a", "achievements"))
+
+var (
+	backKey = key.NewBinding(key.WithKeys("esc", "ctrl+h"), key.WithHelp("esc", "back"))
+	quitKey = key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "quit"))
+
+	doneStyle  = lipgloss.NewStyle().Foreground(color.Orange)
+	nameStyle  = lipgloss.NewStyle().Width(18)
+	countStyle = lipgloss.NewStyle().Width(8)
+	toastStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(color.Orange).
+			Padding(0, 1)
+)
+
+// Gallery is the screen of all the achievements of a player, it goes back to the parent with esc
+type Gallery struct {
+	tracker *Tracker
+	parent  tea.Model
+	help    help.Model
+}
+
+func NewGallery(t *Tracker, parent tea.Model) *Gallery {
+	return &Gallery{tracker: t, parent: parent, help: help.New()}
+}
+
+func (g *Gallery) Init() tea.Cmd { return nil }
+
+func (g *Gallery) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, quitKey):
+			return g, tea.Quit
+		case key.Matches(msg, backKey):
+			return g.parent, nil
+		}
+	}
+	return g, nil
+}
+
+func (g *Gallery) View() string {
+	done := 0
+	rows := make([]string, 0, len(g.tracker.achievements))
+	for _, a := range g.tracker.achievements {
+		mark := "☆"
+		name := a.Name
+		if g.tracker.Done(a) {
+			done++
+			mark = doneStyle.Render("★")
+			name = doneStyle.Render(name)
+		}
+		count := ""
+		if a.Goal > 1 && !g.tracker.Done(a) {
+			count = fmt.Sprintf("%d/%d", g.tracker.Progress(a), a.Goal)
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top,
+			mark+" ", nameStyle.Render(name), countStyle.Render(count), style.Help.Render(a.Desc)))
+	}
+	title := lipgloss.JoinHorizontal(lipgloss.Top,
+		style.Title.Render("Achievements"), "  ", style.Help.Render(fmt.Sprintf("%d/%d", done, len(rows))))
+	return lipgloss.NewStyle().Padding(1, 3).Render(lipgloss.JoinVertical(lipgloss.Left,
+		title,
+		"",
+		lipgloss.JoinVertical(lipgloss.Left, rows...),
+		"",
+		g.help.ShortHelpView([]key.Binding{backKey, quitKey}),
+	))
+}
+
+// Toast is the notification of a unlocked
+func Toast(a *Achievement) string {
+	return toastStyle.Render(doneStyle.Render("★ "+a.Name) + "  " + a.Desc)
+}
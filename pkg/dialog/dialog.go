@@ -23,6 +23,7 @@ var (
 				Padding(1, 1)
 	DefaultWhiteSpaceForground = color.Faint
 	starStyle                  = lipgloss.NewStyle().Foreground(color.Orange)
+	footerStyle                = lipgloss.NewStyle().Foreground(color.Faint)
 )
 
 func Error(message string) *Dialog {
@@ -49,6 +50,12 @@ func (d *Dialog) Stars(total, erned int) *Dialog {
 	return d
 }
 
+// Footer shows s under the message, like a hint of the keys
+func (d *Dialog) Footer(s string) *Dialog {
+	d.footer = s
+	return d
+}
+
 func (d *Dialog) WhiteSpaceChars(chs string) *Dialog {
 	d.whiteSpaceChars = chs
 	return d
@@ -62,6 +69,9 @@ func (d *Dialog) String() string {
 		stars := starStyle.Render(strings.Repeat(starCh, d.ernedStars)) + strings.Repeat(starOutlineCh, d.totalStars-d.ernedStars)
 		d.message = lipgloss.JoinVertical(lipgloss.Center, stars, d.message)
 	}
+	if d.footer != "" {
+		d.message = lipgloss.JoinVertical(lipgloss.Center, d.message, footerStyle.Render(d.footer))
+	}
 	return lipgloss.Place(d.width, d.height,
 		lipgloss.Center, lipgloss.Center,
 		d.borderStyle.Render(d.message),
@@ -120,6 +130,7 @@ type Dialog struct {
 	borderStyle         *lipgloss.Style
 	contentStyle        *lipgloss.Style
 	message             string
+	footer              string
 	whiteSpaceChars     string
 	width               int
 	height              int
@@ -0,0 +1,167 @@
+// Package ballsort is the engine of Ball Sort, the balls are numbered by their colors from 1,
+// the top balls of the same color pour into a tube which is empty or has the same color on top,
+// the game is won when every tube is empty or full of one color.
+package ballsort
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+
+	"github.com/zrcoder/rdor/pkg/engine"
+)
+
+// Game is the state of the tubes
+type Game struct {
+	b        board
+	capacity int
+	// hidden marks the balls not seen yet in the same layout as b
+	hidden []bool
+	steps  int
+}
+
+var _ engine.Engine[Move] = (*Game)(nil)
+
+// New returns the game of the tubes, every tube lists its balls from the bottom up
+func New(tubes [][]int, capacity int) (*Game, error) {
+	buf := make([]byte, 0, len(tubes)*capacity)
+	for i, t := range tubes {
+		if len(t) > capacity {
+			return nil, fmt.Errorf("tube %d holds more than %d balls", i+1, capacity)
+		}
+		for _, c := range t {
+			if c < 1 || c > 255 {
+				return nil, fmt.Errorf("invalid color %d in tube %d", c, i+1)
+			}
+			buf = append(buf, byte(c))
+		}
+		for n := capacity - len(t); n > 0; n-- {
+			buf = append(buf, 0)
+		}
+	}
+	return newGame(board(buf), capacity), nil
+}
+
+// Generate returns a random game with colors*capacity balls in colors+empties tubes,
+// which takes at least minMoves to sort if possible, and one of its shortest solutions
+func Generate(rd *rand.Rand, colors, empties, capacity, minMoves int) (*Game, []Move) {
+	b, solution := generate(rd, colors, empties, capacity, minMoves)
+	return newGame(b, capacity), solution
+}
+
+func newGame(b board, capacity int) *Game {
+	return &Game{b: b, capacity: capacity, hidden: make([]bool, len(b))}
+}
+
+// Hide hides the balls under the tops, they show up once they are on the top
+func (g *Game) Hide() {
+	for i := 0; i < g.Tubes(); i++ {
+		for j := 0; j < height(g.b.tube(i, g.capacity))-1; j++ {
+			g.hidden[i*g.capacity+j] = true
+		}
+	}
+}
+
+// Tubes is the count of the tubes
+func (g *Game) Tubes() int {
+	return g.b.tubes(g.capacity)
+}
+
+// Capacity is the balls a tube holds at most
+func (g *Game) Capacity() int {
+	return g.capacity
+}
+
+// Tube returns the colors of the balls in tube i from the bottom up
+func (g *Game) Tube(i int) []int {
+	t := g.b.tube(i, g.capacity)
+	res := make([]int, height(t))
+	for j := range res {
+		res[j] = int(t[j])
+	}
+	return res
+}
+
+// Hidden reports whether the ball j from the bottom of tube i is hidden
+func (g *Game) Hidden(i, j int) bool {
+	return g.hidden[i*g.capacity+j]
+}
+
+// Hiding reports whether any ball is still hidden
+func (g *Game) Hiding() bool {
+	return slices.Contains(g.hidden, true)
+}
+
+// Done reports whether tube i is full of the balls of one color, all seen
+func (g *Game) Done(i int) bool {
+	t := g.b.tube(i, g.capacity)
+	if height(t) != g.capacity || !uniform(t) {
+		return false
+	}
+	for j := 0; j < g.capacity; j++ {
+		if g.Hidden(i, j) {
+			return false
+		}
+	}
+	return true
+}
+
+// Steps is the count of the moves taken
+func (g *Game) Steps() int {
+	return g.steps
+}
+
+// Solve finds one of the shortest solutions from now on,
+// ok is false if there is none or it's too hard to search
+func (g *Game) Solve() (moves []Move, ok bool) {
+	return solve(g.b, g.capacity)
+}
+
+// Moves returns all the legal moves
+func (g *Game) Moves() []Move {
+	var res []Move
+	for i := 0; i < g.Tubes(); i++ {
+		for j := 0; j < g.Tubes(); j++ {
+			if m := (Move{From: i, To: j}); g.legal(m) {
+				res = append(res, m)
+			}
+		}
+	}
+	return res
+}
+
+func (g *Game) legal(m Move) bool {
+	n := g.Tubes()
+	return m.From >= 0 && m.From < n && m.To >= 0 && m.To < n && g.b.canMove(m, g.capacity)
+}
+
+// Apply pours the top balls of the same color as many as the target tube can hold
+func (g *Game) Apply(m Move) error {
+	if !g.legal(m) {
+		return engine.ErrIllegal
+	}
+	next := g.b.apply(m, g.capacity)
+	hf := height(g.b.tube(m.From, g.capacity))
+	ht := height(g.b.tube(m.To, g.capacity))
+	moved := ht
+	// the balls poured are of the color on the top, so they are all seen
+	for moved < g.capacity && next[m.To*g.capacity+moved] != 0 {
+		g.hidden[m.To*g.capacity+moved] = false
+		moved++
+	}
+	left := hf - (moved - ht)
+	for j := left; j < hf; j++ {
+		g.hidden[m.From*g.capacity+j] = false
+	}
+	if left > 0 {
+		g.hidden[m.From*g.capacity+left-1] = false
+	}
+	g.b = next
+	g.steps++
+	return nil
+}
+
+// Won reports whether every tube is empty or full of one color
+func (g *Game) Won() bool {
+	return g.b.sorted(g.capacity)
+}
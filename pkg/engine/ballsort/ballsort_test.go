@@ -0,0 +1,110 @@
+package ballsort
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/zrcoder/rdor/pkg/engine"
+)
+
+// two colors crossed in two full tubes, with an empty tube
+var crossed = [][]int{{1, 2}, {2, 1}, {}}
+
+func TestMoves(t *testing.T) {
+	tests := []struct {
+		name  string
+		moves []Move
+		want  []Move
+	}{
+		{"start", nil, []Move{{0, 2}, {1, 2}}},
+		{"one poured", []Move{{0, 2}}, []Move{{1, 0}}},
+		{"won", []Move{{0, 2}, {1, 0}, {1, 2}}, []Move{{0, 1}, {2, 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := New(crossed, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, m := range tt.moves {
+				if err := g.Apply(m); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := g.Moves(); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		moves []Move
+		err   error
+		won   bool
+	}{
+		{"legal", []Move{{0, 2}}, nil, false},
+		{"same tube", []Move{{0, 0}}, engine.ErrIllegal, false},
+		{"out of range", []Move{{0, 3}}, engine.ErrIllegal, false},
+		{"empty tube", []Move{{2, 0}}, engine.ErrIllegal, false},
+		{"full tube", []Move{{0, 1}}, engine.ErrIllegal, false},
+		{"other color", []Move{{0, 2}, {1, 2}}, engine.ErrIllegal, false},
+		{"won", []Move{{0, 2}, {1, 0}, {1, 2}}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := New(crossed, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, m := range tt.moves {
+				if err = g.Apply(m); err != nil {
+					break
+				}
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("got error %v, want %v", err, tt.err)
+			}
+			if g.Won() != tt.won {
+				t.Errorf("got won %t, want %t", g.Won(), tt.won)
+			}
+		})
+	}
+}
+
+func TestPourHiddenRun(t *testing.T) {
+	g, err := New([][]int{{2, 1, 1, 1}, {1}, {2, 2, 2}}, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Hiding() {
+		t.Error("no ball should be hidden before hiding")
+	}
+	g.Hide()
+	if !g.Hidden(0, 1) || !g.Hidden(0, 2) || !g.Hiding() {
+		t.Fatal("the balls under the top should be hidden")
+	}
+	if err := g.Apply(Move{From: 0, To: 1}); err != nil {
+		t.Fatal(err)
+	}
+	for j := range g.Tube(1) {
+		if g.Hidden(1, j) {
+			t.Errorf("the ball %d poured should be seen", j)
+		}
+	}
+	if g.Hidden(0, 0) {
+		t.Error("the new top of the source should be seen")
+	}
+	if !g.Done(1) {
+		t.Error("the tube full of one color should be done")
+	}
+	if err := g.Apply(Move{From: 0, To: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if !g.Won() {
+		t.Error("the sorted board should be won")
+	}
+}
@@ -0,0 +1,231 @@
+package ballsort
+
+import (
+	"container/heap"
+	"math/rand"
+	"slices"
+	"strings"
+)
+
+// solveLimit is the max states the solver expands before giving up
+const solveLimit = 300_000
+
+// board is the balls of all the tubes, every tube takes capacity bytes from bottom to top,
+// the colors start from 1 and 0 means no ball
+type board string
+
+// Move pours the top balls of tube From into tube To
+type Move struct {
+	From, To int
+}
+
+func (b board) tube(i, capacity int) string {
+	return string(b[i*capacity : (i+1)*capacity])
+}
+
+func (b board) tubes(capacity int) int {
+	return len(b) / capacity
+}
+
+func height(tube string) int {
+	return strings.IndexByte(tube+"\x00", 0)
+}
+
+// uniform reports whether all the balls in the tube are the same color
+func uniform(tube string) bool {
+	h := height(tube)
+	return h == 0 || strings.Count(tube[:h], tube[:1]) == h
+}
+
+func (b board) sorted(capacity int) bool {
+	for i := 0; i < b.tubes(capacity); i++ {
+		t := b.tube(i, capacity)
+		if h := height(t); h != 0 && (h != capacity || !uniform(t)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (b board) canMove(m Move, capacity int) bool {
+	from, to := b.tube(m.From, capacity), b.tube(m.To, capacity)
+	hf, ht := height(from), height(to)
+	if m.From == m.To || hf == 0 || ht == capacity {
+		return false
+	}
+	return ht == 0 || to[ht-1] == from[hf-1]
+}
+
+// apply pours the top balls with the same color as many as the target tube can hold
+func (b board) apply(m Move, capacity int) board {
+	res := []byte(b)
+	from, to := m.From*capacity, m.To*capacity
+	hf, ht := height(b.tube(m.From, capacity)), height(b.tube(m.To, capacity))
+	c := res[from+hf-1]
+	for hf > 0 && res[from+hf-1] == c && ht < capacity {
+		res[to+ht] = c
+		res[from+hf-1] = 0
+		hf--
+		ht++
+	}
+	return board(res)
+}
+
+// moves returns the useful moves
+func (b board) moves(capacity int) []Move {
+	n := b.tubes(capacity)
+	res := make([]Move, 0, n)
+	for i := 0; i < n; i++ {
+		from := b.tube(i, capacity)
+		if height(from) == 0 || height(from) == capacity && uniform(from) {
+			continue
+		}
+		movedToEmpty := false
+		for j := 0; j < n; j++ {
+			m := Move{From: i, To: j}
+			if !b.canMove(m, capacity) {
+				continue
+			}
+			if height(b.tube(j, capacity)) == 0 {
+				// moving all the same balls into an empty tube is useless,
+				// and the empty tubes are all alike
+				if uniform(from) || movedToEmpty {
+					continue
+				}
+				movedToEmpty = true
+			}
+			res = append(res, m)
+		}
+	}
+	return res
+}
+
+// canonical ignores the order of the tubes
+func (b board) canonical(capacity int) string {
+	ts := make([]string, b.tubes(capacity))
+	for i := range ts {
+		ts[i] = b.tube(i, capacity)
+	}
+	slices.Sort(ts)
+	return strings.Join(ts, "")
+}
+
+// estimate is a lower bound of the moves left:
+// every group of the same balls above the bottom run of its tube is poured at least once,
+// so are the bottom runs except one of each color
+func (b board) estimate(capacity int) int {
+	res := 0
+	bottoms := map[byte]bool{}
+	for i := 0; i < b.tubes(capacity); i++ {
+		t := b.tube(i, capacity)
+		h := height(t)
+		if h == 0 {
+			continue
+		}
+		run := 1
+		for run < h && t[run] == t[0] {
+			run++
+		}
+		for j := run; j < h; j++ {
+			if t[j] != t[j-1] {
+				res++
+			}
+		}
+		if bottoms[t[0]] {
+			res++
+		}
+		bottoms[t[0]] = true
+	}
+	return res
+}
+
+// solve finds the shortest moves to sort the board with A*,
+// ok is false if it's unsolvable or more than solveLimit states are expanded
+func solve(b board, capacity int) (moves []Move, ok bool) {
+	start := &node{board: b, f: b.estimate(capacity)}
+	open := &nodeHeap{start}
+	best := map[string]int{b.canonical(capacity): 0}
+	for expanded := 0; open.Len() > 0 && expanded < solveLimit; expanded++ {
+		cur := heap.Pop(open).(*node)
+		if cur.board.sorted(capacity) {
+			for n := cur; n.parent != nil; n = n.parent {
+				moves = append(moves, n.move)
+			}
+			slices.Reverse(moves)
+			return moves, true
+		}
+		for _, m := range cur.board.moves(capacity) {
+			next := cur.board.apply(m, capacity)
+			key := next.canonical(capacity)
+			if g, seen := best[key]; seen && g <= cur.g+1 {
+				continue
+			}
+			best[key] = cur.g + 1
+			heap.Push(open, &node{
+				board:  next,
+				g:      cur.g + 1,
+				f:      cur.g + 1 + next.estimate(capacity),
+				parent: cur,
+				move:   m,
+			})
+		}
+	}
+	return nil, false
+}
+
+// generate returns a random solvable board with colors*capacity balls in colors+empties tubes,
+// which takes at least minMoves to sort, or the hardest one after some tries
+func generate(rd *rand.Rand, colors, empties, capacity, minMoves int) (board, []Move) {
+	const maxTries = 20
+	balls := make([]byte, 0, colors*capacity)
+	for c := 1; c <= colors; c++ {
+		for i := 0; i < capacity; i++ {
+			balls = append(balls, byte(c))
+		}
+	}
+	var (
+		res      board
+		solution []Move
+	)
+	for tries := 0; tries < maxTries || res == ""; tries++ {
+		rd.Shuffle(len(balls), func(i, j int) {
+			balls[i], balls[j] = balls[j], balls[i]
+		})
+		b := board(string(balls) + strings.Repeat("\x00", empties*capacity))
+		moves, ok := solve(b, capacity)
+		if !ok || len(moves) <= len(solution) {
+			continue
+		}
+		res, solution = b, moves
+		if len(moves) >= minMoves {
+			break
+		}
+	}
+	return res, solution
+}
+
+type node struct {
+	board  board
+	parent *node
+	move   Move
+	g, f   int
+}
+
+type nodeHeap []*node
+
+func (h nodeHeap) Len() int { return len(h) }
+func (h nodeHeap) Less(i, j int) bool {
+	if h[i].f == h[j].f {
+		return h[i].g > h[j].g
+	}
+	return h[i].f < h[j].f
+}
+func (h nodeHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *nodeHeap) Push(x any)   { *h = append(*h, x.(*node)) }
+func (h *nodeHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
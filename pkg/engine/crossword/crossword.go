@@ -0,0 +1,309 @@
+// Package crossword is the engine of the crossword, the blanks of a grid are filled with the candidates,
+// a word is checked once all its cells are filled.
+package crossword
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/zrcoder/rdor/pkg/engine"
+	"github.com/zrcoder/rdor/pkg/grid"
+)
+
+// Size is the rows and the columns of the grid
+const Size = 9
+
+// State is the state of a cell
+type State int
+
+const (
+	// Placed is a candidate filled in a word not checked yet
+	Placed State = iota
+	Blank
+	Right
+	Wrong
+)
+
+// Cell is a cell of the grid, the cells given or filled rightly are fixed
+type Cell struct {
+	Char  rune
+	State State
+	// candidate is the index in the candidates, dest is where it belongs
+	candidate int
+	dest      int
+}
+
+// Fixed reports whether the cell can't be changed any more
+func (c *Cell) Fixed() bool {
+	return c != nil && c.State == Right
+}
+
+// Move fills the candidate at Pos, a Candidate of -1 takes back the one filled at Pos
+type Move struct {
+	Pos       grid.Position
+	Candidate int
+}
+
+// Game is the state of a level
+type Game struct {
+	grid       *grid.Grid[*Cell]
+	candidates []*Cell
+	blanks     int
+	mistakes   int
+	hints      int
+	completed  []string
+}
+
+var _ engine.Engine[Move] = (*Game)(nil)
+
+// New creates a level, the lines are the rows of the grid, where empty is no cell and blank is a cell to fill,
+// the candidate i belongs at answerPos[i], which is row*Size+col, or -1 for a decoy
+func New(lines []string, candidates string, answerPos []int, empty, blank rune) (*Game, error) {
+	if len(lines) > Size {
+		return nil, errors.New("too many rows")
+	}
+	g := &Game{grid: grid.New[*Cell](Size, Size)}
+	for i, row := range lines {
+		if utf8.RuneCountInString(row) > Size {
+			return nil, fmt.Errorf("too many characters in row %d", i+1)
+		}
+		for j, v := range []rune(row) {
+			switch v {
+			case empty:
+			case blank:
+				g.blanks++
+				g.grid.Set(grid.Position{Row: i, Col: j}, &Cell{State: Blank})
+			default:
+				g.grid.Set(grid.Position{Row: i, Col: j}, &Cell{Char: v, State: Right})
+			}
+		}
+	}
+	if g.blanks == 0 {
+		return nil, errors.New("no blanks to fill")
+	}
+	chars := []rune(candidates)
+	if len(chars) != len(answerPos) {
+		return nil, fmt.Errorf("%d candidates with %d answer positions", len(chars), len(answerPos))
+	}
+	g.candidates = make([]*Cell, len(chars))
+	for i, v := range chars {
+		g.candidates[i] = &Cell{Char: v, candidate: i, dest: answerPos[i]}
+	}
+	return g, nil
+}
+
+// Grid is the cells of the level, nil for no cell, which should not be changed
+func (g *Game) Grid() *grid.Grid[*Cell] {
+	return g.grid
+}
+
+// Candidates returns the candidates, the ones filled are 0
+func (g *Game) Candidates() []rune {
+	res := make([]rune, len(g.candidates))
+	for i, c := range g.candidates {
+		if c != nil {
+			res[i] = c.Char
+		}
+	}
+	return res
+}
+
+// Blanks is the count of the cells not fixed yet
+func (g *Game) Blanks() int {
+	return g.blanks
+}
+
+// Mistakes is the count of the words filled wrongly
+func (g *Game) Mistakes() int {
+	return g.mistakes
+}
+
+// Hints is the count of the cells filled by Hint
+func (g *Game) Hints() int {
+	return g.hints
+}
+
+// Completed returns the words filled rightly, in the order they were completed
+func (g *Game) Completed() []string {
+	return g.completed
+}
+
+func (g *Game) Moves() []Move {
+	var res []Move
+	g.grid.Range(func(pos grid.Position, cell *Cell, _ bool) (end bool) {
+		if cell == nil || cell.Fixed() {
+			return
+		}
+		for i, c := range g.candidates {
+			if c != nil {
+				res = append(res, Move{Pos: pos, Candidate: i})
+			}
+		}
+		if cell.State != Blank {
+			res = append(res, Move{Pos: pos, Candidate: -1})
+		}
+		return
+	})
+	return res
+}
+
+func (g *Game) legal(m Move) bool {
+	if g.grid.OutBound(m.Pos) {
+		return false
+	}
+	cell := g.grid.Get(m.Pos)
+	if cell == nil || cell.Fixed() {
+		return false
+	}
+	if m.Candidate == -1 {
+		return cell.State != Blank
+	}
+	return m.Candidate >= 0 && m.Candidate < len(g.candidates) && g.candidates[m.Candidate] != nil
+}
+
+// Apply fills or takes back a candidate, the words across Pos are checked once they are filled up
+func (g *Game) Apply(m Move) error {
+	if !g.legal(m) {
+		return engine.ErrIllegal
+	}
+	cur := g.grid.Get(m.Pos)
+	if m.Candidate == -1 {
+		g.grid.Set(m.Pos, &Cell{State: Blank})
+		g.putBack(cur)
+		return nil
+	}
+	cell := g.candidates[m.Candidate]
+	g.candidates[m.Candidate] = nil
+	g.grid.Set(m.Pos, cell)
+	if cur.State != Blank {
+		g.putBack(cur)
+	}
+	if !g.check(m.Pos) {
+		g.mistakes++
+	}
+	return nil
+}
+
+// Hint fills the right candidate at pos, taking it from another cell if it was filled there
+func (g *Game) Hint(pos grid.Position) error {
+	if g.grid.OutBound(pos) {
+		return engine.ErrIllegal
+	}
+	cur := g.grid.Get(pos)
+	if cur == nil || cur.Fixed() {
+		return engine.ErrIllegal
+	}
+	dest := pos.Row*Size + pos.Col
+	var cell *Cell
+	if i := slices.IndexFunc(g.candidates, func(c *Cell) bool {
+		return c != nil && c.dest == dest
+	}); i != -1 {
+		cell = g.candidates[i]
+		g.candidates[i] = nil
+	} else {
+		g.grid.Range(func(p grid.Position, c *Cell, _ bool) (end bool) {
+			if c != nil && c.State != Blank && !c.Fixed() && c.dest == dest {
+				cell = c
+				g.grid.Set(p, &Cell{State: Blank})
+				return true
+			}
+			return false
+		})
+	}
+	if cell == nil {
+		return engine.ErrIllegal
+	}
+	if cur.State != Blank {
+		g.putBack(cur)
+	}
+	cell.State = Right
+	g.blanks--
+	g.hints++
+	g.grid.Set(pos, cell)
+	g.check(pos)
+	return nil
+}
+
+// Won reports whether all the cells are fixed
+func (g *Game) Won() bool {
+	return g.blanks == 0
+}
+
+func (g *Game) putBack(c *Cell) {
+	c.State = Placed
+	g.candidates[c.candidate] = c
+}
+
+// check checks the horizontal and the vertical words across pos, both are checked even if one is wrong,
+// so a right word is fixed though the word crossing it is wrong
+func (g *Game) check(pos grid.Position) bool {
+	left, right := pos.Col, pos.Col
+	for ; left >= 0 && g.grid.Getrc(pos.Row, left) != nil; left-- {
+	}
+	for ; right < Size && g.grid.Getrc(pos.Row, right) != nil; right++ {
+	}
+	horizontal := g.checkWord(pos.Row, left+1, pos.Row, right-1)
+	up, down := pos.Row, pos.Row
+	for ; up >= 0 && g.grid.Getrc(up, pos.Col) != nil; up-- {
+	}
+	for ; down < Size && g.grid.Getrc(down, pos.Col) != nil; down++ {
+	}
+	vertical := g.checkWord(up+1, pos.Col, down-1, pos.Col)
+	return horizontal && vertical
+}
+
+// checkWord checks the whole word once it's filled up
+func (g *Game) checkWord(startR, startC, endR, endC int) bool {
+	if startR == endR && startC == endC {
+		return true
+	}
+	for i := startR; i <= endR; i++ {
+		for j := startC; j <= endC; j++ {
+			if g.grid.Getrc(i, j).State == Blank {
+				return true
+			}
+		}
+	}
+	ok := true
+	for i := startR; i <= endR && ok; i++ {
+		for j := startC; j <= endC; j++ {
+			if c := g.grid.Getrc(i, j); !c.Fixed() && c.dest != i*Size+j {
+				ok = false
+				break
+			}
+		}
+	}
+	if ok {
+		g.complete(startR, startC, endR, endC)
+	}
+	for i := startR; i <= endR; i++ {
+		for j := startC; j <= endC; j++ {
+			c := g.grid.Getrc(i, j)
+			if c.Fixed() {
+				continue
+			}
+			if ok {
+				c.State = Right
+				g.blanks--
+			} else {
+				c.State = Wrong
+			}
+		}
+	}
+	return ok
+}
+
+func (g *Game) complete(startR, startC, endR, endC int) {
+	buf := strings.Builder{}
+	for i := startR; i <= endR; i++ {
+		for j := startC; j <= endC; j++ {
+			buf.WriteRune(g.grid.Getrc(i, j).Char)
+		}
+	}
+	if word := buf.String(); !slices.Contains(g.completed, word) {
+		g.completed = append(g.completed, word)
+	}
+}
@@ -0,0 +1,124 @@
+package crossword
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/zrcoder/rdor/pkg/engine"
+	"github.com/zrcoder/rdor/pkg/grid"
+)
+
+// the word ABC across the first row with C to fill, and D is a decoy
+func newGame(t *testing.T) *Game {
+	t.Helper()
+	g, err := New([]string{"AB_"}, "CD", []int{2, -1}, '.', '_')
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+var blank = grid.Position{Row: 0, Col: 2}
+
+func TestMoves(t *testing.T) {
+	tests := []struct {
+		name  string
+		moves []Move
+		want  []Move
+	}{
+		{"start", nil, []Move{{blank, 0}, {blank, 1}}},
+		{"filled wrongly", []Move{{blank, 1}}, []Move{{blank, 0}, {blank, -1}}},
+		{"won", []Move{{blank, 0}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newGame(t)
+			for _, m := range tt.moves {
+				if err := g.Apply(m); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := g.Moves(); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		moves    []Move
+		err      error
+		mistakes int
+		won      bool
+	}{
+		{"right", []Move{{blank, 0}}, nil, 0, true},
+		{"wrong", []Move{{blank, 1}}, nil, 1, false},
+		{"wrong then right", []Move{{blank, 1}, {blank, 0}}, nil, 1, true},
+		{"take back", []Move{{blank, 1}, {blank, -1}, {blank, 0}}, nil, 1, true},
+		{"given cell", []Move{{grid.Position{Row: 0, Col: 0}, 0}}, engine.ErrIllegal, 0, false},
+		{"no cell", []Move{{grid.Position{Row: 0, Col: 3}, 0}}, engine.ErrIllegal, 0, false},
+		{"out of the grid", []Move{{grid.Position{Row: Size, Col: 0}, 0}}, engine.ErrIllegal, 0, false},
+		{"take back a blank", []Move{{blank, -1}}, engine.ErrIllegal, 0, false},
+		{"candidate used", []Move{{blank, 1}, {blank, 1}}, engine.ErrIllegal, 1, false},
+		{"fixed cell", []Move{{blank, 0}, {blank, 1}}, engine.ErrIllegal, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newGame(t)
+			var err error
+			for _, m := range tt.moves {
+				if err = g.Apply(m); err != nil {
+					break
+				}
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("got error %v, want %v", err, tt.err)
+			}
+			if g.Mistakes() != tt.mistakes {
+				t.Errorf("got %d mistakes, want %d", g.Mistakes(), tt.mistakes)
+			}
+			if g.Won() != tt.won {
+				t.Errorf("got won %t, want %t", g.Won(), tt.won)
+			}
+		})
+	}
+}
+
+func TestHint(t *testing.T) {
+	g := newGame(t)
+	if err := g.Apply(Move{blank, 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Hint(blank); err != nil {
+		t.Fatal(err)
+	}
+	if !g.Won() || g.Hints() != 1 || !slices.Equal(g.Completed(), []string{"ABC"}) {
+		t.Errorf("got won %t with %d hints and words %v", g.Won(), g.Hints(), g.Completed())
+	}
+	if err := g.Hint(blank); !errors.Is(err, engine.ErrIllegal) {
+		t.Errorf("got error %v hinting a fixed cell", err)
+	}
+}
+
+func TestCrossing(t *testing.T) {
+	// the word A?? across the first row crosses ?D down the last column
+	g, err := New([]string{"A__", "..D"}, "BCX", []int{1, 2, -1}, '.', '_')
+	if err != nil {
+		t.Fatal(err)
+	}
+	mid, corner := grid.Position{Row: 0, Col: 1}, grid.Position{Row: 0, Col: 2}
+	for _, m := range []Move{{mid, 2}, {corner, 1}} {
+		if err := g.Apply(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !g.Grid().Get(corner).Fixed() || !slices.Equal(g.Completed(), []string{"CD"}) {
+		t.Errorf("the word down should be right, got the words %v", g.Completed())
+	}
+	if g.Grid().Get(mid).State != Wrong || g.Mistakes() != 1 || g.Blanks() != 1 {
+		t.Errorf("the word across should be wrong, got %d mistakes and %d blanks", g.Mistakes(), g.Blanks())
+	}
+}
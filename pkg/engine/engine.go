@@ -0,0 +1,19 @@
+// Package engine is the rules of the games without any rendering,
+// the sub packages hold the engine of every game,
+// so bots, solvers and tests can play the games without a terminal.
+package engine
+
+import "errors"
+
+// Engine is the state of a game, which only changes by applying moves
+type Engine[M any] interface {
+	// Moves returns the legal moves in the current state
+	Moves() []M
+	// Apply makes the move m, the state is untouched if the move is illegal
+	Apply(m M) error
+	// Won reports whether the game is won
+	Won() bool
+}
+
+// ErrIllegal is returned by applying an illegal move
+var ErrIllegal = errors.New("illegal move")
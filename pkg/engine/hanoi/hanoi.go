@@ -0,0 +1,99 @@
+// Package hanoi is the engine of the Tower of Hanoi,
+// the disks are numbered by their sizes, 1 is the smallest.
+package hanoi
+
+import (
+	"errors"
+
+	"github.com/zrcoder/rdor/pkg/engine"
+)
+
+// Piles is the count of the piles, all the disks start from the first one and go to the last one
+const Piles = 3
+
+// ErrLarger is returned by moving a disk above a smaller one
+var ErrLarger = errors.New("can not move the disk above a smaller one")
+
+// Move moves the top disk of pile From to pile To
+type Move struct {
+	From, To int
+}
+
+// Game is the state of the piles
+type Game struct {
+	piles [Piles][]int
+	disks int
+	steps int
+}
+
+var _ engine.Engine[Move] = (*Game)(nil)
+
+// New returns the game with n disks on the first pile
+func New(n int) *Game {
+	g := &Game{disks: n}
+	for i := n; i > 0; i-- {
+		g.piles[0] = append(g.piles[0], i)
+	}
+	return g
+}
+
+// Disks is the count of the disks
+func (g *Game) Disks() int {
+	return g.disks
+}
+
+// Pile returns the disks of pile i from the bottom up, which should not be changed
+func (g *Game) Pile(i int) []int {
+	return g.piles[i]
+}
+
+// Steps is the count of the moves taken
+func (g *Game) Steps() int {
+	return g.steps
+}
+
+// MinSteps is the least moves to win
+func (g *Game) MinSteps() int {
+	return 1<<g.disks - 1
+}
+
+func (g *Game) Moves() []Move {
+	var res []Move
+	for from := range g.piles {
+		for to := range g.piles {
+			if g.check(Move{From: from, To: to}) == nil {
+				res = append(res, Move{From: from, To: to})
+			}
+		}
+	}
+	return res
+}
+
+func (g *Game) check(m Move) error {
+	if m.From == m.To || m.From < 0 || m.From >= Piles || m.To < 0 || m.To >= Piles || len(g.piles[m.From]) == 0 {
+		return engine.ErrIllegal
+	}
+	if to := g.piles[m.To]; len(to) > 0 && to[len(to)-1] < g.top(m.From) {
+		return ErrLarger
+	}
+	return nil
+}
+
+func (g *Game) top(i int) int {
+	return g.piles[i][len(g.piles[i])-1]
+}
+
+func (g *Game) Apply(m Move) error {
+	if err := g.check(m); err != nil {
+		return err
+	}
+	g.piles[m.To] = append(g.piles[m.To], g.top(m.From))
+	g.piles[m.From] = g.piles[m.From][:len(g.piles[m.From])-1]
+	g.steps++
+	return nil
+}
+
+// Won reports whether all the disks are on the last pile
+func (g *Game) Won() bool {
+	return len(g.piles[Piles-1]) == g.disks
+}
@@ -0,0 +1,81 @@
+package hanoi
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/zrcoder/rdor/pkg/engine"
+)
+
+func TestMoves(t *testing.T) {
+	tests := []struct {
+		name  string
+		moves []Move
+		want  []Move
+	}{
+		{"start", nil, []Move{{0, 1}, {0, 2}}},
+		{"one moved", []Move{{0, 1}}, []Move{{0, 2}, {1, 0}, {1, 2}}},
+		{"won", []Move{{0, 1}, {0, 2}, {1, 2}}, []Move{{2, 0}, {2, 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := New(2)
+			for _, m := range tt.moves {
+				if err := g.Apply(m); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := g.Moves(); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		moves []Move
+		err   error
+		won   bool
+	}{
+		{"legal", []Move{{0, 1}}, nil, false},
+		{"same pile", []Move{{0, 0}}, engine.ErrIllegal, false},
+		{"out of range", []Move{{0, Piles}}, engine.ErrIllegal, false},
+		{"negative", []Move{{-1, 0}}, engine.ErrIllegal, false},
+		{"empty pile", []Move{{1, 2}}, engine.ErrIllegal, false},
+		{"larger above", []Move{{0, 1}, {0, 1}}, ErrLarger, false},
+		{"on the middle pile", []Move{{0, 2}, {0, 1}, {2, 1}}, nil, false},
+		{"won", []Move{{0, 1}, {0, 2}, {1, 2}}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := New(2)
+			var err error
+			for _, m := range tt.moves {
+				if err = g.Apply(m); err != nil {
+					break
+				}
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("got error %v, want %v", err, tt.err)
+			}
+			if g.Won() != tt.won {
+				t.Errorf("got won %t, want %t", g.Won(), tt.won)
+			}
+		})
+	}
+}
+
+func TestSteps(t *testing.T) {
+	g := New(3)
+	if g.MinSteps() != 7 {
+		t.Errorf("got min steps %d, want 7", g.MinSteps())
+	}
+	g.Apply(Move{0, 2})
+	g.Apply(Move{0, 0})
+	if g.Steps() != 1 {
+		t.Errorf("got steps %d, want 1, the illegal moves don't count", g.Steps())
+	}
+}
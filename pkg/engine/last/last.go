@@ -0,0 +1,69 @@
+// Package last is the engine of Last, two players take turns to eat the cells,
+// a player eats one cell at least and max cells at most in a turn,
+// the other player can only be eaten after all the cells,
+// and the one eats the other wins, or loses in misère.
+package last
+
+import "github.com/zrcoder/rdor/pkg/engine"
+
+// Game is the state of a game, the moves are the counts of the cells to eat
+type Game struct {
+	left   int
+	max    int
+	misere bool
+	moves  int
+}
+
+var _ engine.Engine[int] = (*Game)(nil)
+
+// New returns the game of the cells besides the two players
+func New(cells, max int, misere bool) *Game {
+	return &Game{left: cells + 1, max: max, misere: misere}
+}
+
+// Left is the cells left to eat, including the other player
+func (g *Game) Left() int {
+	return g.left
+}
+
+// Max is the most cells to eat in a turn
+func (g *Game) Max() int {
+	return g.max
+}
+
+// Misere reports whether the one eats the other loses
+func (g *Game) Misere() bool {
+	return g.misere
+}
+
+// Turns is the count of the moves taken, the player to move is Turns()%2 from the first one
+func (g *Game) Turns() int {
+	return g.moves
+}
+
+// Over reports whether a player has been eaten
+func (g *Game) Over() bool {
+	return g.left == 0
+}
+
+func (g *Game) Moves() []int {
+	var res []int
+	for n := 1; n <= min(g.left, g.max); n++ {
+		res = append(res, n)
+	}
+	return res
+}
+
+func (g *Game) Apply(n int) error {
+	if n < 1 || n > min(g.left, g.max) {
+		return engine.ErrIllegal
+	}
+	g.left -= n
+	g.moves++
+	return nil
+}
+
+// Won reports whether the player moved last has won
+func (g *Game) Won() bool {
+	return g.Over() && !g.misere
+}
@@ -0,0 +1,107 @@
+package last
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/zrcoder/rdor/pkg/engine"
+	"github.com/zrcoder/rdor/pkg/store"
+)
+
+func TestMoves(t *testing.T) {
+	tests := []struct {
+		name       string
+		cells, max int
+		want       []int
+	}{
+		{"more cells than max", 5, 3, []int{1, 2, 3}},
+		{"fewer cells than max", 1, 3, []int{1, 2}},
+		{"only the other", 0, 3, []int{1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(tt.cells, tt.max, false).Moves(); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name   string
+		misere bool
+		moves  []int
+		err    error
+		left   int
+		won    bool
+	}{
+		{"legal", false, []int{2}, nil, 2, false},
+		{"none", false, []int{0}, engine.ErrIllegal, 4, false},
+		{"more than max", false, []int{3}, engine.ErrIllegal, 4, false},
+		{"more than left", false, []int{2, 2, 1}, engine.ErrIllegal, 0, true},
+		{"eat the other", false, []int{2, 2}, nil, 0, true},
+		{"eat the other in misere", true, []int{2, 2}, nil, 0, false},
+		{"not over", true, []int{2, 1}, nil, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := New(3, 2, tt.misere)
+			var err error
+			for _, n := range tt.moves {
+				if err = g.Apply(n); err != nil {
+					break
+				}
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("got error %v, want %v", err, tt.err)
+			}
+			if g.Left() != tt.left {
+				t.Errorf("got left %d, want %d", g.Left(), tt.left)
+			}
+			if g.Won() != tt.won {
+				t.Errorf("got won %t, want %t", g.Won(), tt.won)
+			}
+		})
+	}
+}
+
+func TestMinimaxMistakes(t *testing.T) {
+	tests := []struct {
+		rate float64
+		name string
+	}{
+		{0, "minimax(0% mistakes)"},
+		{DefaultMistakeRate, "minimax(20% mistakes)"},
+		{1, "minimax(100% mistakes)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rd := rand.New(rand.NewSource(1))
+			var o Opponent
+			for _, op := range NewOpponents(rd, store.Store{Dir: t.TempDir()}, OpponentOptions{MistakeRate: tt.rate}) {
+				if strings.HasPrefix(op.Name(), "minimax") {
+					o = op
+				}
+			}
+			if o == nil || o.Name() != tt.name {
+				t.Fatalf("got the minimax opponent %v, want %s", o, tt.name)
+			}
+			// leaving 3 of the 10 cells wins with 2 at most in a turn, the other choice is a mistake
+			mistakes := 0
+			for range 1000 {
+				if o.Eat(10, 2, false) != 1 {
+					mistakes++
+				}
+			}
+			// half of the random moves are right by chance
+			if want := tt.rate / 2 * 1000; math.Abs(float64(mistakes)-want) > 50 {
+				t.Errorf("got %d mistakes in 1000 turns, want about %.0f", mistakes, want)
+			}
+		})
+	}
+}
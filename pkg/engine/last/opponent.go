@@ -0,0 +1,215 @@
+package last
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/zrcoder/rdor/pkg/store"
+)
+
+// Opponent decides how many cells the rival eats in its turn
+type Opponent interface {
+	Name() string
+	// Eat returns the cells to eat in the turn, left is the cells left including the other player,
+	// who can only be eaten at last, max is the limit of each turn,
+	// and the one eats the last loses in misère
+	Eat(left, max int, misere bool) int
+}
+
+// Learner is an opponent learning from the results of the games
+type Learner interface {
+	Opponent
+	NewGame()
+	Learn(won bool) error
+}
+
+const (
+	// DefaultMistakeRate is how often the minimax opponent makes a random move by default
+	DefaultMistakeRate = 0.2
+	learnerStore       = "last-learner"
+)
+
+// OpponentOptions tune the opponents
+type OpponentOptions struct {
+	// MistakeRate is how often the minimax opponent makes a random move, from 0 to 1
+	MistakeRate float64
+}
+
+// NewOpponents returns all the opponents, sharing rd for the random choices,
+// the learner keeps what it learned in st
+func NewOpponents(rd *rand.Rand, st store.Store, opts OpponentOptions) []Opponent {
+	return []Opponent{
+		&randomOpponent{rd: rd},
+		&greedyOpponent{},
+		&perfectOpponent{rd: rd},
+		&minimaxOpponent{rd: rd, mistakeRate: opts.MistakeRate},
+		&learningOpponent{rd: rd, store: st},
+	}
+}
+
+// randomOpponent just takes random cells
+type randomOpponent struct {
+	rd *rand.Rand
+}
+
+func (o *randomOpponent) Name() string { return "random" }
+
+func (o *randomOpponent) Eat(left, max int, _ bool) int {
+	return 1 + o.rd.Intn(min(left, max))
+}
+
+// greedyOpponent eats as much as it can, but not the last in misère
+type greedyOpponent struct{}
+
+func (o *greedyOpponent) Name() string { return "greedy" }
+
+func (o *greedyOpponent) Eat(left, max int, misere bool) int {
+	if misere && left > 1 && left <= max {
+		return left - 1
+	}
+	return min(left, max)
+}
+
+// perfectOpponent leaves a multiple of max+1 cells to the player whenever it can,
+// or one more than that in misère
+type perfectOpponent struct {
+	rd *rand.Rand
+}
+
+func (o *perfectOpponent) Name() string { return "perfect" }
+
+func (o *perfectOpponent) Eat(left, max int, misere bool) int {
+	target := left
+	if misere {
+		target--
+	}
+	if n := target % (max + 1); n != 0 {
+		return n
+	}
+	return 1 + o.rd.Intn(min(left, max))
+}
+
+// minimaxOpponent searches the game tree, but makes a random move at mistakeRate
+type minimaxOpponent struct {
+	rd          *rand.Rand
+	mistakeRate float64
+	memo        map[minimaxState]bool
+}
+
+type minimaxState struct {
+	left, max int
+	misere    bool
+}
+
+func (o *minimaxOpponent) Name() string {
+	return fmt.Sprintf("minimax(%d%% mistakes)", int(o.mistakeRate*100))
+}
+
+func (o *minimaxOpponent) Eat(left, max int, misere bool) int {
+	if o.rd.Float64() >= o.mistakeRate {
+		for n := 1; n <= min(left, max); n++ {
+			if o.winning(left, n, max, misere) {
+				return n
+			}
+		}
+	}
+	return 1 + o.rd.Intn(min(left, max))
+}
+
+// winning reports whether eating n of the left cells wins
+func (o *minimaxOpponent) winning(left, n, max int, misere bool) bool {
+	if n == left {
+		return !misere
+	}
+	return !o.wins(minimaxState{left: left - n, max: max, misere: misere})
+}
+
+// wins reports whether the player to eat wins
+func (o *minimaxOpponent) wins(s minimaxState) bool {
+	if o.memo == nil {
+		o.memo = map[minimaxState]bool{}
+	}
+	if res, ok := o.memo[s]; ok {
+		return res
+	}
+	res := false
+	for n := 1; n <= min(s.left, s.max) && !res; n++ {
+		res = o.winning(s.left, n, s.max, s.misere)
+	}
+	o.memo[s] = res
+	return res
+}
+
+// learningOpponent keeps weights for every choice like matchboxes,
+// picks by the weights and rewards the choices of the games it won.
+// The weights are stored locally, so it gets better game by game.
+type learningOpponent struct {
+	rd      *rand.Rand
+	store   store.Store
+	weights map[string][]int
+	history []choice
+}
+
+type choice struct {
+	key string
+	n   int
+}
+
+const (
+	initialWeight = 3
+	winReward     = 3
+	losePenalty   = 1
+)
+
+func (o *learningOpponent) Name() string { return "learner" }
+
+func (o *learningOpponent) NewGame() {
+	o.history = o.history[:0]
+}
+
+func (o *learningOpponent) Eat(left, max int, misere bool) int {
+	if o.weights == nil {
+		o.weights = map[string][]int{}
+		// start from scratch if nothing learned could be loaded
+		_ = o.store.Load(learnerStore, &o.weights)
+	}
+	k := fmt.Sprintf("%d/%d", left, max)
+	if misere {
+		k += "/misere"
+	}
+	ws := o.weights[k]
+	if len(ws) != max {
+		ws = make([]int, max)
+		for i := range ws {
+			ws[i] = initialWeight
+		}
+		o.weights[k] = ws
+	}
+	total := 0
+	for _, w := range ws[:min(left, max)] {
+		total += w
+	}
+	r := o.rd.Intn(total)
+	n := 1
+	for ; r >= ws[n-1]; n++ {
+		r -= ws[n-1]
+	}
+	o.history = append(o.history, choice{key: k, n: n})
+	return n
+}
+
+func (o *learningOpponent) Learn(won bool) error {
+	if len(o.history) == 0 {
+		return nil
+	}
+	for _, c := range o.history {
+		ws := o.weights[c.key]
+		if won {
+			ws[c.n-1] += winReward
+		} else {
+			ws[c.n-1] = max(1, ws[c.n-1]-losePenalty)
+		}
+	}
+	o.history = o.history[:0]
+	return o.store.Save(learnerStore, o.weights)
+}
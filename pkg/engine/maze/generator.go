@@ -0,0 +1,73 @@
+package maze
+
+import (
+	"math/rand"
+	"strings"
+)
+
+// Generate makes a perfect maze of rows x cols cells by randomized depth-first search,
+// the player starts at the bottom left
+// and the goal is put in the farthest cell from the player
+func Generate(rd *rand.Rand, rows, cols int) string {
+	lines := make([][]byte, 2*rows+1)
+	for r := range lines {
+		line := []byte(strings.Repeat(" ", 4*cols+1))
+		for c := range line {
+			switch {
+			case r%2 == 0 && c%4 == 0:
+				line[c] = Corner
+			case r%2 == 0:
+				line[c] = HorizontalWall
+			case c%4 == 0:
+				line[c] = VerticalWall
+			}
+		}
+		lines[r] = line
+	}
+
+	type cell struct{ r, c int }
+	dirs := []cell{{-1, 0}, {1, 0}, {0, -1}, {0, 1}}
+	// carve removes the wall between the cell a and its neighbour b
+	carve := func(a, b cell) {
+		r, c := a.r+b.r+1, 2*(a.c+b.c)+2
+		if a.r == b.r {
+			lines[r][c] = Blank
+		} else {
+			lines[r][c-1], lines[r][c], lines[r][c+1] = Blank, Blank, Blank
+		}
+	}
+	start := cell{rows - 1, 0}
+	dist := map[cell]int{start: 0}
+	far := start
+	stack := []cell{start}
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		var next []cell
+		for _, d := range dirs {
+			nb := cell{cur.r + d.r, cur.c + d.c}
+			if _, seen := dist[nb]; !seen && nb.r >= 0 && nb.r < rows && nb.c >= 0 && nb.c < cols {
+				next = append(next, nb)
+			}
+		}
+		if len(next) == 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		nb := next[rd.Intn(len(next))]
+		carve(cur, nb)
+		dist[nb] = dist[cur] + 1
+		if dist[nb] > dist[far] {
+			far = nb
+		}
+		stack = append(stack, nb)
+	}
+	lines[2*start.r+1][4*start.c+2] = Player
+	lines[2*far.r+1][4*far.c+2] = Goal
+
+	buf := &strings.Builder{}
+	for _, line := range lines {
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
@@ -0,0 +1,122 @@
+// Package maze is the engine of Maze, a level is drawn with characters,
+// the cells are 3 characters wide between the corners,
+// the player walks through the cells to take all the goals.
+package maze
+
+import (
+	"fmt"
+
+	"github.com/zrcoder/rdor/pkg/engine"
+	"github.com/zrcoder/rdor/pkg/grid"
+)
+
+// the characters of a level
+const (
+	VerticalWall   = '|'
+	HorizontalWall = '-'
+	Corner         = 'o'
+	Player         = 'S'
+	Goal           = 'G'
+	Blank          = ' '
+)
+
+// Game is the state of a level
+type Game struct {
+	grid   *grid.Grid[rune]
+	player grid.Position
+	goals  map[grid.Position]bool
+	steps  int
+}
+
+var _ engine.Engine[grid.Direction] = (*Game)(nil)
+
+// Parse reads a level from its characters
+func Parse(s string) (*Game, error) {
+	g := &Game{grid: grid.NewWithString(s), goals: map[grid.Position]bool{}}
+	var err error
+	players := 0
+	g.grid.Range(func(pos grid.Position, char rune, _ bool) (end bool) {
+		switch char {
+		case Player:
+			g.player = pos
+			players++
+		case Goal:
+			g.goals[pos] = true
+		case VerticalWall, HorizontalWall, Corner, Blank:
+		default:
+			err = fmt.Errorf("invalid character %q at %d:%d", char, pos.Row+1, pos.Col+1)
+			return true
+		}
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+	if players != 1 {
+		return nil, fmt.Errorf("a level should have one player, got %d", players)
+	}
+	return g, nil
+}
+
+// Grid is the current characters of the level, which should not be changed
+func (g *Game) Grid() *grid.Grid[rune] {
+	return g.grid
+}
+
+// Steps is the count of the moves taken
+func (g *Game) Steps() int {
+	return g.steps
+}
+
+// Goals is the count of the goals left
+func (g *Game) Goals() int {
+	return len(g.goals)
+}
+
+// scale is the distance between two cells in direction d
+func scale(d grid.Direction) grid.Direction {
+	if d.Dx != 0 {
+		return d.Scale(2)
+	}
+	return d
+}
+
+func (g *Game) legal(d grid.Direction) bool {
+	d = scale(d)
+	wall := grid.TransForm(g.player, d)
+	if g.grid.OutBound(wall) {
+		return false
+	}
+	char := g.grid.Get(wall)
+	return char != VerticalWall && char != HorizontalWall && !g.grid.OutBound(grid.TransForm(wall, d))
+}
+
+func (g *Game) Moves() []grid.Direction {
+	var res []grid.Direction
+	for _, d := range grid.NormalDirections {
+		if g.legal(d) {
+			res = append(res, d)
+		}
+	}
+	return res
+}
+
+// Apply moves the player to the next cell in direction d
+func (g *Game) Apply(d grid.Direction) error {
+	if !g.legal(d) {
+		return engine.ErrIllegal
+	}
+	d = scale(d)
+	pos := grid.TransForm(grid.TransForm(g.player, d), d)
+	delete(g.goals, pos)
+	g.grid.Set(pos, Player)
+	g.grid.Set(g.player, Blank)
+	g.player = pos
+	g.steps++
+	return nil
+}
+
+// Won reports whether all the goals are taken
+func (g *Game) Won() bool {
+	return len(g.goals) == 0
+}
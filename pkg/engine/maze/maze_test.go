@@ -0,0 +1,94 @@
+package maze
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/zrcoder/rdor/pkg/engine"
+	"github.com/zrcoder/rdor/pkg/grid"
+)
+
+const (
+	row = `o---o---o---o
+| G   S   G |
+o---o---o---o`
+	column = `o---o
+| S |
+o   o
+| G |
+o---o`
+	walled = `o---o---o
+| S | G |
+o---o---o`
+)
+
+func TestMoves(t *testing.T) {
+	tests := []struct {
+		name  string
+		level string
+		want  []grid.Direction
+	}{
+		{"row", row, []grid.Direction{grid.Left, grid.Right}},
+		{"column", column, []grid.Direction{grid.Down}},
+		{"walled", walled, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := Parse(tt.level)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := g.Moves(); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		level string
+		moves []grid.Direction
+		err   error
+		goals int
+	}{
+		{"one goal", row, []grid.Direction{grid.Right}, nil, 1},
+		{"all goals", row, []grid.Direction{grid.Right, grid.Left, grid.Left}, nil, 0},
+		{"out of the maze", row, []grid.Direction{grid.Right, grid.Right}, engine.ErrIllegal, 1},
+		{"horizontal wall", row, []grid.Direction{grid.Up}, engine.ErrIllegal, 2},
+		{"vertical wall", walled, []grid.Direction{grid.Right}, engine.ErrIllegal, 1},
+		{"down", column, []grid.Direction{grid.Down}, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := Parse(tt.level)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, d := range tt.moves {
+				if err = g.Apply(d); err != nil {
+					break
+				}
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("got error %v, want %v", err, tt.err)
+			}
+			if g.Goals() != tt.goals {
+				t.Errorf("got %d goals left, want %d", g.Goals(), tt.goals)
+			}
+			if g.Won() != (tt.goals == 0) {
+				t.Errorf("got won %t with %d goals left", g.Won(), tt.goals)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, s := range []string{"o---o\n| G |\no---o", "o---o\n|S S|\no---o", "o---o\n| S#|\no---o"} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("expected an error parsing %q", s)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Package npuzzle is the engine of the N-Puzzle, the tiles of an n✗n board are numbered from 1,
+// the blank is 0, the tiles slide into the blank till they are in order with the blank at last.
+package npuzzle
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/zrcoder/rdor/pkg/engine"
+	"github.com/zrcoder/rdor/pkg/grid"
+)
+
+// Game is the state of a board
+type Game struct {
+	n     int
+	b     board
+	blank int
+	steps int
+}
+
+var _ engine.Engine[grid.Direction] = (*Game)(nil)
+
+// New returns the game of the n✗n board with tiles in row-major order
+func New(n int, tiles []int) (*Game, error) {
+	if len(tiles) != n*n {
+		return nil, fmt.Errorf("a %d✗%d board should have %d tiles, got %d", n, n, n*n, len(tiles))
+	}
+	seen := make([]bool, n*n)
+	for _, t := range tiles {
+		if t < 0 || t >= n*n || seen[t] {
+			return nil, fmt.Errorf("tiles should be a permutation of 0-%d", n*n-1)
+		}
+		seen[t] = true
+	}
+	b := board(append([]int(nil), tiles...))
+	if !b.solvable(n) {
+		return nil, fmt.Errorf("the board can not be solved")
+	}
+	return newGame(n, b), nil
+}
+
+// Random returns the game of a uniformly random solvable board
+func Random(n int, rd *rand.Rand) *Game {
+	return newGame(n, randomBoard(n, rd))
+}
+
+// Shuffled returns the game of a random board whose optimal solution takes dist moves
+func Shuffled(n, dist int, rd *rand.Rand) (*Game, error) {
+	b, err := boardAt(n, dist, rd)
+	if err != nil {
+		return nil, err
+	}
+	return newGame(n, b), nil
+}
+
+func newGame(n int, b board) *Game {
+	return &Game{n: n, b: b, blank: b.blank()}
+}
+
+// Size is n of the n✗n board
+func (g *Game) Size() int {
+	return g.n
+}
+
+// Tile returns the tile at row r and column c, 0 for the blank
+func (g *Game) Tile(r, c int) int {
+	return g.b[r*g.n+c]
+}
+
+// Steps is the count of the moves taken
+func (g *Game) Steps() int {
+	return g.steps
+}
+
+// Optimal returns the least moves to solve the board from now on,
+// it's only a lower bound if not exact, when the board is too hard to search
+func (g *Game) Optimal() (moves int, exact bool) {
+	return solve(g.n, g.b)
+}
+
+// from returns the index of the tile to slide in direction d, or -1 if there is none
+func (g *Game) from(d grid.Direction) int {
+	r, c := g.blank/g.n-d.Dy, g.blank%g.n-d.Dx
+	if r < 0 || r >= g.n || c < 0 || c >= g.n {
+		return -1
+	}
+	return r*g.n + c
+}
+
+func (g *Game) Moves() []grid.Direction {
+	var res []grid.Direction
+	for _, d := range grid.NormalDirections {
+		if g.from(d) != -1 {
+			res = append(res, d)
+		}
+	}
+	return res
+}
+
+// Apply slides the tile next to the blank in direction d into the blank
+func (g *Game) Apply(d grid.Direction) error {
+	i := g.from(d)
+	if i == -1 {
+		return engine.ErrIllegal
+	}
+	g.b[g.blank], g.b[i] = g.b[i], 0
+	g.blank = i
+	g.steps++
+	return nil
+}
+
+// Won reports whether the tiles are in order
+func (g *Game) Won() bool {
+	for i, t := range g.b[:len(g.b)-1] {
+		if t != i+1 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,109 @@
+package npuzzle
+
+import (
+	"errors"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/zrcoder/rdor/pkg/engine"
+	"github.com/zrcoder/rdor/pkg/grid"
+)
+
+func TestMoves(t *testing.T) {
+	tests := []struct {
+		name  string
+		tiles []int
+		want  []grid.Direction
+	}{
+		{"blank at the last", []int{1, 2, 3, 0}, []grid.Direction{grid.Down, grid.Right}},
+		{"blank at the first", []int{0, 1, 3, 2}, []grid.Direction{grid.Up, grid.Left}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := New(2, tt.tiles)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := g.Moves(); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		moves []grid.Direction
+		err   error
+		tiles []int
+		won   bool
+	}{
+		{"slide down", []grid.Direction{grid.Down}, nil, []int{1, 0, 3, 2}, false},
+		{"slide right", []grid.Direction{grid.Right}, nil, []int{1, 2, 0, 3}, false},
+		{"no tile above the blank", []grid.Direction{grid.Up}, engine.ErrIllegal, []int{1, 2, 3, 0}, true},
+		{"no tile left to the blank", []grid.Direction{grid.Left}, engine.ErrIllegal, []int{1, 2, 3, 0}, true},
+		{"slide back", []grid.Direction{grid.Down, grid.Up}, nil, []int{1, 2, 3, 0}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := New(2, []int{1, 2, 3, 0})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, d := range tt.moves {
+				if err = g.Apply(d); err != nil {
+					break
+				}
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("got error %v, want %v", err, tt.err)
+			}
+			var tiles []int
+			for r := range g.Size() {
+				for c := range g.Size() {
+					tiles = append(tiles, g.Tile(r, c))
+				}
+			}
+			if !slices.Equal(tiles, tt.tiles) {
+				t.Errorf("got tiles %v, want %v", tiles, tt.tiles)
+			}
+			if g.Won() != tt.won {
+				t.Errorf("got won %t, want %t", g.Won(), tt.won)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name  string
+		tiles []int
+	}{
+		{"too few tiles", []int{1, 2, 0}},
+		{"repeated tile", []int{1, 1, 3, 0}},
+		{"unsolvable", []int{2, 1, 3, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(2, tt.tiles); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestShuffled(t *testing.T) {
+	for seed := int64(1); seed <= 20; seed++ {
+		for _, dist := range []int{8, 14, 20} {
+			g, err := Shuffled(3, dist, rand.New(rand.NewSource(seed)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, exact := solve(3, g.b); got != dist || !exact {
+				t.Errorf("seed %d: got a board %d moves away, want %d", seed, got, dist)
+			}
+		}
+	}
+}
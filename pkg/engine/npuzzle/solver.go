@@ -0,0 +1,227 @@
+package npuzzle
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+const (
+	// solveLimit is the max nodes the solver expands before giving up
+	solveLimit = 2_000_000
+	// maxWalk bounds the steps of the walk to a board dist moves away by dist*maxWalk
+	maxWalk = 100
+)
+
+// board is the puzzle in row-major order, 0 is the blank and tile t belongs at index t-1
+type board []int
+
+func goalBoard(n int) board {
+	b := make(board, n*n)
+	for i := range b {
+		b[i] = i + 1
+	}
+	b[len(b)-1] = 0
+	return b
+}
+
+func (b board) blank() int {
+	for i, t := range b {
+		if t == 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// solvable reports whether the goal can be reached from b,
+// see https://en.wikipedia.org/wiki/15_puzzle#Solvability
+func (b board) solvable(n int) bool {
+	inversions := 0
+	for i := range b {
+		for j := i + 1; j < len(b); j++ {
+			if b[i] != 0 && b[j] != 0 && b[i] > b[j] {
+				inversions++
+			}
+		}
+	}
+	if n%2 == 1 {
+		return inversions%2 == 0
+	}
+	rowFromBottom := n - b.blank()/n
+	return (inversions+rowFromBottom)%2 == 1
+}
+
+func neighbors(n, i int) []int {
+	res := make([]int, 0, 4)
+	if i >= n {
+		res = append(res, i-n)
+	}
+	if i < n*n-n {
+		res = append(res, i+n)
+	}
+	if i%n > 0 {
+		res = append(res, i-1)
+	}
+	if i%n < n-1 {
+		res = append(res, i+1)
+	}
+	return res
+}
+
+// randomBoard returns a uniformly random solvable board
+func randomBoard(n int, rd *rand.Rand) board {
+	b := goalBoard(n)
+	rd.Shuffle(len(b), func(i, j int) {
+		b[i], b[j] = b[j], b[i]
+	})
+	if !b.solvable(n) {
+		// swapping two tiles flips the parity
+		i, j := 0, 1
+		if b[i] == 0 {
+			i = 2
+		} else if b[j] == 0 {
+			j = 2
+		}
+		b[i], b[j] = b[j], b[i]
+	}
+	return b
+}
+
+// boardAt returns a random board whose optimal solution takes exactly dist moves.
+// Every move changes the optimal distance by exactly one,
+// so it walks away from the goal and only keeps the moves that increase the distance.
+// It fails if the solver gives up on a board or the walk takes too long.
+func boardAt(n, dist int, rd *rand.Rand) (board, error) {
+	b := goalBoard(n)
+	blank, prev, cur := len(b)-1, -1, 0
+	for steps := 0; cur < dist; steps++ {
+		if steps >= dist*maxWalk {
+			return nil, fmt.Errorf("no board found %d moves away in %d steps", dist, steps)
+		}
+		nbs := neighbors(n, blank)
+		rd.Shuffle(len(nbs), func(i, j int) {
+			nbs[i], nbs[j] = nbs[j], nbs[i]
+		})
+		moved, back := false, -1
+		for _, next := range nbs {
+			if next == prev {
+				continue
+			}
+			b[blank], b[next] = b[next], 0
+			s := newSolver(n, b)
+			closer := s.within(cur - 1)
+			if s.nodes > solveLimit {
+				return nil, fmt.Errorf("the distance of the board is unknown after %d nodes searched", solveLimit)
+			}
+			if !closer {
+				blank, prev, cur = next, blank, cur+1
+				moved = true
+				break
+			}
+			b[next], b[blank] = b[blank], 0
+			back = next
+		}
+		if !moved {
+			// stuck on a local maximum, step back another way than undoing the last move,
+			// there is always one as every cell has at least two neighbors
+			b[blank], b[back] = b[back], 0
+			blank, prev, cur = back, blank, cur-1
+		}
+	}
+	return b, nil
+}
+
+// solve returns the optimal number of moves to sort b with IDA*.
+// If the search expands more than solveLimit nodes it gives up,
+// and the returned moves is just a lower bound.
+func solve(n int, b board) (moves int, exact bool) {
+	s := newSolver(n, b)
+	h := s.manhattan()
+	bound := h
+	for {
+		t := s.search(s.b.blank(), -1, 0, h, bound)
+		if t == found {
+			return bound, true
+		}
+		if s.nodes > solveLimit {
+			return bound, false
+		}
+		bound = t
+	}
+}
+
+const found = -1
+
+type solver struct {
+	b     board
+	n     int
+	nodes int
+}
+
+func newSolver(n int, b board) *solver {
+	s := &solver{n: n, b: make(board, len(b))}
+	copy(s.b, b)
+	return s
+}
+
+// within reports whether b can be sorted in depth moves
+func (s *solver) within(depth int) bool {
+	if depth < 0 {
+		return false
+	}
+	return s.search(s.b.blank(), -1, 0, s.manhattan(), depth) == found
+}
+
+func (s *solver) search(blank, prev, g, h, bound int) int {
+	f := g + h
+	if f > bound {
+		return f
+	}
+	if h == 0 {
+		return found
+	}
+	s.nodes++
+	if s.nodes > solveLimit {
+		return math.MaxInt
+	}
+	minF := math.MaxInt
+	for _, next := range neighbors(s.n, blank) {
+		if next == prev {
+			continue
+		}
+		tile := s.b[next]
+		dh := s.distance(tile, blank) - s.distance(tile, next)
+		s.b[blank], s.b[next] = tile, 0
+		t := s.search(next, blank, g+1, h+dh, bound)
+		s.b[blank], s.b[next] = 0, tile
+		if t == found {
+			return found
+		}
+		minF = min(minF, t)
+	}
+	return minF
+}
+
+func (s *solver) manhattan() int {
+	res := 0
+	for i, t := range s.b {
+		if t != 0 {
+			res += s.distance(t, i)
+		}
+	}
+	return res
+}
+
+// distance returns the manhattan distance between tile's goal index and i
+func (s *solver) distance(tile, i int) int {
+	goal := tile - 1
+	return abs(goal/s.n-i/s.n) + abs(goal%s.n-i%s.n)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,91 @@
+package point24
+
+import (
+	"errors"
+)
+
+var ErrDivideByZero = errors.New("can not divide by zero")
+
+// Expr is a card on the table, a number or the combination of two cards
+type Expr struct {
+	val         rat
+	left, right *Expr
+	op          string
+}
+
+func num(n int) *Expr {
+	return &Expr{val: newRat(int64(n), 1)}
+}
+
+func combine(op string, left, right *Expr) (*Expr, error) {
+	var val rat
+	switch op {
+	case Plus:
+		val = left.val.add(right.val)
+	case Minus:
+		val = left.val.sub(right.val)
+	case Times:
+		val = left.val.mul(right.val)
+	case Divide:
+		if right.val.zero() {
+			return nil, ErrDivideByZero
+		}
+		val = left.val.quo(right.val)
+	}
+	return &Expr{val: val, op: op, left: left, right: right}, nil
+}
+
+func (e *Expr) Leaf() bool {
+	return e.op == ""
+}
+
+func (e *Expr) Equals(n int) bool {
+	return e.val == newRat(int64(n), 1)
+}
+
+func (e *Expr) Value() string {
+	return e.val.String()
+}
+
+// String returns the expression with the minimal parentheses
+func (e *Expr) String() string {
+	if e.Leaf() {
+		return e.Value()
+	}
+	left, right := e.left.String(), e.right.String()
+	if !e.left.Leaf() && precedence(e.left.op) < precedence(e.op) {
+		left = "(" + left + ")"
+	}
+	if !e.right.Leaf() && (precedence(e.right.op) < precedence(e.op) ||
+		precedence(e.right.op) == precedence(e.op) && (e.op == Minus || e.op == Divide)) {
+		right = "(" + right + ")"
+	}
+	return left + " " + e.op + " " + right
+}
+
+func precedence(op string) int {
+	switch op {
+	case Plus, Minus:
+		return 1
+	case Times, Divide:
+		return 2
+	}
+	return 0
+}
+
+func commutative(op string) bool {
+	return op == Plus || op == Times
+}
+
+// key returns the canonical form of the expression,
+// the operands of commutative operators are ordered
+func (e *Expr) key() string {
+	if e.Leaf() {
+		return e.Value()
+	}
+	left, right := e.left.key(), e.right.key()
+	if commutative(e.op) && left > right {
+		left, right = right, left
+	}
+	return "(" + left + e.op + right + ")"
+}
@@ -0,0 +1,126 @@
+// Package point24 is the engine of 24 Points, two cards combine into a new one with an operator,
+// the game is won when the last card equals the target.
+package point24
+
+import (
+	"slices"
+
+	"github.com/zrcoder/rdor/pkg/engine"
+)
+
+// the operators
+const (
+	Plus   = "+"
+	Minus  = "-"
+	Times  = "×"
+	Divide = "÷"
+)
+
+// MaxCards is the most cards of a hand
+const MaxCards = 6
+
+// Move combines the card Left and the card Right with Op, the new card takes the place of Right
+type Move struct {
+	Left, Right int
+	Op          string
+}
+
+// Game is the state of a hand
+type Game struct {
+	target  int
+	cards   []*Expr
+	history [][]*Expr
+	steps   int
+}
+
+var _ engine.Engine[Move] = (*Game)(nil)
+
+// New returns the game of the hand to combine into target
+func New(hand []int, target int) *Game {
+	g := &Game{target: target, cards: make([]*Expr, len(hand))}
+	for i, v := range hand {
+		g.cards[i] = num(v)
+	}
+	return g
+}
+
+// Target is the number to combine the cards into
+func (g *Game) Target() int {
+	return g.target
+}
+
+// Cards returns the cards in their places, nil for the places combined away, which should not be changed
+func (g *Game) Cards() []*Expr {
+	return g.cards
+}
+
+// Steps is the count of the moves applied, the undone ones included
+func (g *Game) Steps() int {
+	return g.steps
+}
+
+func (g *Game) Moves() []Move {
+	var res []Move
+	for i, left := range g.cards {
+		for j, right := range g.cards {
+			if i == j || left == nil || right == nil {
+				continue
+			}
+			for _, op := range opers {
+				if _, err := combine(op, left, right); err == nil {
+					res = append(res, Move{Left: i, Right: j, Op: op})
+				}
+			}
+		}
+	}
+	return res
+}
+
+func (g *Game) Apply(m Move) error {
+	n := len(g.cards)
+	if m.Left == m.Right || m.Left < 0 || m.Left >= n || m.Right < 0 || m.Right >= n ||
+		g.cards[m.Left] == nil || g.cards[m.Right] == nil || !slices.Contains(opers, m.Op) {
+		return engine.ErrIllegal
+	}
+	res, err := combine(m.Op, g.cards[m.Left], g.cards[m.Right])
+	if err != nil {
+		return err
+	}
+	g.history = append(g.history, slices.Clone(g.cards))
+	g.cards[m.Right] = res
+	g.cards[m.Left] = nil
+	g.steps++
+	return nil
+}
+
+// Undo takes back the last move, it returns false if there is nothing to undo
+func (g *Game) Undo() bool {
+	n := len(g.history)
+	if n == 0 {
+		return false
+	}
+	g.cards = g.history[n-1]
+	g.history = g.history[:n-1]
+	return true
+}
+
+// Result returns the last card, or nil if there are more cards
+func (g *Game) Result() *Expr {
+	var res *Expr
+	for _, card := range g.cards {
+		if card == nil {
+			continue
+		}
+		if res != nil {
+			return nil
+		}
+		res = card
+	}
+	return res
+}
+
+// Won reports whether the last card equals the target
+func (g *Game) Won() bool {
+	res := g.Result()
+	return res != nil && res.Equals(g.target)
+}
@@ -0,0 +1,84 @@
+package point24
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/zrcoder/rdor/pkg/engine"
+)
+
+func TestMoves(t *testing.T) {
+	tests := []struct {
+		name string
+		hand []int
+		want []Move
+	}{
+		{"two cards", []int{2, 1}, []Move{
+			{0, 1, Plus}, {0, 1, Minus}, {0, 1, Times}, {0, 1, Divide},
+			{1, 0, Plus}, {1, 0, Minus}, {1, 0, Times}, {1, 0, Divide},
+		}},
+		{"divide by zero", []int{2, 0}, []Move{
+			{0, 1, Plus}, {0, 1, Minus}, {0, 1, Times},
+			{1, 0, Plus}, {1, 0, Minus}, {1, 0, Times}, {1, 0, Divide},
+		}},
+		{"one card", []int{24}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(tt.hand, 24).Moves(); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		hand  []int
+		moves []Move
+		err   error
+		won   bool
+	}{
+		{"legal", []int{1, 2, 3, 4}, []Move{{0, 1, Plus}}, nil, false},
+		{"same card", []int{1, 2, 3, 4}, []Move{{0, 0, Plus}}, engine.ErrIllegal, false},
+		{"out of range", []int{1, 2, 3, 4}, []Move{{0, 4, Plus}}, engine.ErrIllegal, false},
+		{"unknown operator", []int{1, 2, 3, 4}, []Move{{0, 1, "%"}}, engine.ErrIllegal, false},
+		{"card combined away", []int{1, 2, 3, 4}, []Move{{0, 1, Plus}, {0, 2, Plus}}, engine.ErrIllegal, false},
+		{"divide by zero", []int{1, 1, 2}, []Move{{0, 1, Minus}, {2, 1, Divide}}, ErrDivideByZero, false},
+		{"won", []int{1, 2, 3, 4}, []Move{{0, 1, Plus}, {1, 2, Plus}, {2, 3, Times}}, nil, true},
+		{"missed", []int{1, 2, 3, 4}, []Move{{0, 1, Plus}, {1, 2, Plus}, {2, 3, Plus}}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := New(tt.hand, 24)
+			var err error
+			for _, m := range tt.moves {
+				if err = g.Apply(m); err != nil {
+					break
+				}
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("got error %v, want %v", err, tt.err)
+			}
+			if g.Won() != tt.won {
+				t.Errorf("got won %t, want %t", g.Won(), tt.won)
+			}
+		})
+	}
+}
+
+func TestUndo(t *testing.T) {
+	g := New([]int{1, 2, 3, 4}, 24)
+	if g.Undo() {
+		t.Error("nothing to undo at the start")
+	}
+	g.Apply(Move{0, 1, Plus})
+	if !g.Undo() {
+		t.Fatal("expected to undo the move")
+	}
+	if g.Cards()[0] == nil || g.Cards()[1].Value() != "2" || g.Steps() != 1 {
+		t.Errorf("expected the hand as dealt with 1 step, got %d steps", g.Steps())
+	}
+}
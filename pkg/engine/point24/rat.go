@@ -0,0 +1,52 @@
+package point24
+
+import "strconv"
+
+// rat is an exact fraction with a positive denominator,
+// int64 is enough as the cards are no more than 13 and no more than 6 cards a hand
+type rat struct {
+	num, den int64
+}
+
+func newRat(num, den int64) rat {
+	if den < 0 {
+		num, den = -num, -den
+	}
+	g := gcd(abs(num), den)
+	return rat{num: num / g, den: den / g}
+}
+
+func (a rat) add(b rat) rat { return newRat(a.num*b.den+b.num*a.den, a.den*b.den) }
+func (a rat) sub(b rat) rat { return newRat(a.num*b.den-b.num*a.den, a.den*b.den) }
+func (a rat) mul(b rat) rat { return newRat(a.num*b.num, a.den*b.den) }
+func (a rat) quo(b rat) rat { return newRat(a.num*b.den, a.den*b.num) }
+
+func (a rat) zero() bool { return a.num == 0 }
+
+func (a rat) String() string {
+	if a.den == 1 {
+		return strconv.FormatInt(a.num, 10)
+	}
+	return strconv.FormatInt(a.num, 10) + "/" + strconv.FormatInt(a.den, 10)
+}
+
+func (a rat) less(b rat) bool {
+	return a.num*b.den < b.num*a.den
+}
+
+func gcd(a, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	if a == 0 {
+		return 1
+	}
+	return a
+}
+
+func abs(x int64) int64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
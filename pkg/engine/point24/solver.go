@@ -0,0 +1,107 @@
+package point24
+
+import (
+	"slices"
+)
+
+// MaxSolutions limits the solutions to enumerate, hands with more cards may have thousands
+const MaxSolutions = 50
+
+var opers = []string{Plus, Minus, Times, Divide}
+
+// Solve returns the distinct solutions that combine nums into target, no more than MaxSolutions,
+// the simpler solutions come first
+func Solve(nums []int, target int) []*Expr {
+	cards := make([]*Expr, len(nums))
+	for i, v := range nums {
+		cards[i] = num(v)
+	}
+	s := &solver{
+		target: newRat(int64(target), 1),
+		seen:   map[string]bool{},
+		dead:   map[values]bool{},
+	}
+	s.dfs(cards)
+	slices.SortStableFunc(s.res, func(a, b *Expr) int {
+		return len(a.String()) - len(b.String())
+	})
+	return s.res
+}
+
+type solver struct {
+	target rat
+	seen   map[string]bool
+	// dead memorizes the values of the cards that can't be combined into target
+	dead map[values]bool
+	res  []*Expr
+}
+
+func (s *solver) dfs(cards []*Expr) bool {
+	if len(cards) == 1 {
+		if cards[0].val != s.target {
+			return false
+		}
+		if k := cards[0].key(); !s.seen[k] {
+			s.seen[k] = true
+			s.res = append(s.res, cards[0])
+		}
+		return true
+	}
+	vk := valuesOf(cards)
+	if s.dead[vk] {
+		return false
+	}
+	ok := false
+	for i := range cards {
+		for j := range cards {
+			if i == j {
+				continue
+			}
+			rest := make([]*Expr, 0, len(cards)-1)
+			for k, card := range cards {
+				if k != i && k != j {
+					rest = append(rest, card)
+				}
+			}
+			for _, op := range opers {
+				if commutative(op) && i > j {
+					continue
+				}
+				e, err := combine(op, cards[i], cards[j])
+				if err != nil {
+					continue
+				}
+				if s.dfs(append(rest, e)) {
+					ok = true
+				}
+				if len(s.res) >= MaxSolutions {
+					return true
+				}
+			}
+		}
+	}
+	if !ok {
+		s.dead[vk] = true
+	}
+	return ok
+}
+
+// values is the sorted values of the cards
+type values [MaxCards]rat
+
+func valuesOf(cards []*Expr) values {
+	var res values
+	for i, card := range cards {
+		res[i] = card.val
+	}
+	slices.SortFunc(res[:len(cards)], func(a, b rat) int {
+		switch {
+		case a.less(b):
+			return -1
+		case b.less(a):
+			return 1
+		}
+		return 0
+	})
+	return res
+}
@@ -0,0 +1,196 @@
+// Package sokoban is the engine of Sokoban, a level is a grid of characters,
+// the player pushes all the boxes into the slots to win.
+package sokoban
+
+import (
+	"embed"
+	"fmt"
+	"strconv"
+
+	"github.com/zrcoder/rdor/pkg/engine"
+	"github.com/zrcoder/rdor/pkg/grid"
+)
+
+// the characters of a level
+const (
+	Wall         = '#'
+	Player       = '@'
+	Blank        = ' '
+	Slot         = 'X'
+	Box          = 'O'
+	BoxInSlot    = '*'
+	PlayerInSlot = '.'
+)
+
+// Levels is the count of the embedded levels
+const Levels = 51
+
+//go:embed levels
+var levelsFS embed.FS
+
+// Level returns the embedded level i, counting from 0
+func Level(i int) (*Game, error) {
+	data, err := levelsFS.ReadFile("levels/" + strconv.Itoa(i+1) + ".txt")
+	if err != nil {
+		return nil, err
+	}
+	return Parse(string(data))
+}
+
+// step is a move taken, for undoing
+type step struct {
+	dir    grid.Direction
+	pushed bool
+}
+
+// Game is the state of a level
+type Game struct {
+	grid    *grid.Grid[rune]
+	player  grid.Position
+	history []step
+	pushes  int
+}
+
+var _ engine.Engine[grid.Direction] = (*Game)(nil)
+
+// Parse reads a level from its characters
+func Parse(s string) (*Game, error) {
+	g := &Game{grid: grid.NewWithString(s)}
+	players := 0
+	g.grid.Range(func(pos grid.Position, char rune, _ bool) (end bool) {
+		if char == Player || char == PlayerInSlot {
+			g.player = pos
+			players++
+		}
+		return
+	})
+	if players != 1 {
+		return nil, fmt.Errorf("a level should have one player, got %d", players)
+	}
+	return g, nil
+}
+
+// Grid is the current characters of the level, which should not be changed
+func (g *Game) Grid() *grid.Grid[rune] {
+	return g.grid
+}
+
+// Player returns the position of the player
+func (g *Game) Player() grid.Position {
+	return g.player
+}
+
+// Steps is the count of the moves taken
+func (g *Game) Steps() int {
+	return len(g.history)
+}
+
+// Pushes is the count of the moves pushed a box
+func (g *Game) Pushes() int {
+	return g.pushes
+}
+
+func (g *Game) Moves() []grid.Direction {
+	var res []grid.Direction
+	for _, d := range grid.NormalDirections {
+		if g.legal(d) {
+			res = append(res, d)
+		}
+	}
+	return res
+}
+
+func (g *Game) legal(d grid.Direction) bool {
+	pos := grid.TransForm(g.player, d)
+	switch g.at(pos) {
+	case Blank, Slot:
+		return true
+	case Box, BoxInSlot:
+		dest := g.at(grid.TransForm(pos, d))
+		return dest == Blank || dest == Slot
+	}
+	return false
+}
+
+// at returns the character at pos, a wall for the positions out of the grid
+func (g *Game) at(pos grid.Position) rune {
+	if g.grid.OutBound(pos) {
+		return Wall
+	}
+	return g.grid.Get(pos)
+}
+
+// Apply moves the player in direction d, pushing the box ahead if any
+func (g *Game) Apply(d grid.Direction) error {
+	if !g.legal(d) {
+		return engine.ErrIllegal
+	}
+	pos := grid.TransForm(g.player, d)
+	pushed := false
+	if char := g.grid.Get(pos); char == Box || char == BoxInSlot {
+		g.moveBox(pos, grid.TransForm(pos, d))
+		pushed = true
+		g.pushes++
+	}
+	g.movePlayer(pos)
+	g.history = append(g.history, step{dir: d, pushed: pushed})
+	return nil
+}
+
+// Undo takes back the last move, it returns false if there is nothing to undo
+func (g *Game) Undo() bool {
+	n := len(g.history)
+	if n == 0 {
+		return false
+	}
+	last := g.history[n-1]
+	g.history = g.history[:n-1]
+	prev := grid.TransForm(g.player, last.dir.Opposite())
+	cur := g.player
+	g.movePlayer(prev)
+	if last.pushed {
+		g.moveBox(grid.TransForm(cur, last.dir), cur)
+		g.pushes--
+	}
+	return true
+}
+
+func (g *Game) movePlayer(p grid.Position) {
+	if g.grid.Get(p) == Slot {
+		g.grid.Set(p, PlayerInSlot)
+	} else {
+		g.grid.Set(p, Player)
+	}
+	if g.grid.Get(g.player) == PlayerInSlot {
+		g.grid.Set(g.player, Slot)
+	} else {
+		g.grid.Set(g.player, Blank)
+	}
+	g.player = p
+}
+
+func (g *Game) moveBox(src, dest grid.Position) {
+	if g.grid.Get(dest) == Slot {
+		g.grid.Set(dest, BoxInSlot)
+	} else {
+		g.grid.Set(dest, Box)
+	}
+	if g.grid.Get(src) == BoxInSlot {
+		g.grid.Set(src, Slot)
+	} else {
+		g.grid.Set(src, Blank)
+	}
+}
+
+// Won reports whether all the boxes are in the slots
+func (g *Game) Won() bool {
+	res := true
+	g.grid.Range(func(_ grid.Position, char rune, _ bool) (end bool) {
+		if char == Box {
+			res = false
+			return true
+		}
+		return
+	})
+	return res
+}
@@ -0,0 +1,105 @@
+package sokoban
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/zrcoder/rdor/pkg/engine"
+	"github.com/zrcoder/rdor/pkg/grid"
+)
+
+const (
+	line = `######
+#@ OX#
+######`
+	boxes = `######
+#@OOX#
+######`
+	room = `#####
+# X #
+#O@ #
+#####`
+)
+
+func TestMoves(t *testing.T) {
+	tests := []struct {
+		name  string
+		level string
+		want  []grid.Direction
+	}{
+		{"line", line, []grid.Direction{grid.Right}},
+		{"two boxes ahead", boxes, nil},
+		{"room", room, []grid.Direction{grid.Up, grid.Right}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := Parse(tt.level)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := g.Moves(); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name   string
+		level  string
+		moves  []grid.Direction
+		err    error
+		pushes int
+		won    bool
+	}{
+		{"walk", line, []grid.Direction{grid.Right}, nil, 0, false},
+		{"into the wall", line, []grid.Direction{grid.Left}, engine.ErrIllegal, 0, false},
+		{"push into the slot", line, []grid.Direction{grid.Right, grid.Right}, nil, 1, true},
+		{"push into the wall", line, []grid.Direction{grid.Right, grid.Right, grid.Right}, engine.ErrIllegal, 1, true},
+		{"push two boxes", boxes, []grid.Direction{grid.Right}, engine.ErrIllegal, 0, false},
+		{"box in the corner", room, []grid.Direction{grid.Left}, engine.ErrIllegal, 0, false},
+		{"over the slot", room, []grid.Direction{grid.Up, grid.Right, grid.Down}, nil, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := Parse(tt.level)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, d := range tt.moves {
+				if err = g.Apply(d); err != nil {
+					break
+				}
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("got error %v, want %v", err, tt.err)
+			}
+			if g.Pushes() != tt.pushes {
+				t.Errorf("got %d pushes, want %d", g.Pushes(), tt.pushes)
+			}
+			if g.Won() != tt.won {
+				t.Errorf("got won %t, want %t", g.Won(), tt.won)
+			}
+		})
+	}
+}
+
+func TestUndo(t *testing.T) {
+	g, err := Parse(line)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Undo() {
+		t.Error("nothing to undo at the start")
+	}
+	g.Apply(grid.Right)
+	g.Apply(grid.Right)
+	if !g.Undo() || !g.Undo() {
+		t.Fatal("expected to undo the two moves")
+	}
+	if !g.Grid().Equal(grid.NewWithString(line)) || g.Pushes() != 0 || g.Won() {
+		t.Errorf("expected the level as the start, got %d pushes", g.Pushes())
+	}
+}
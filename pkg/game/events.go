@@ -0,0 +1,49 @@
+package game
+
+// EventKind is the kind of the events of games
+type EventKind int
+
+const (
+	// LevelStarted is emitted whenever a level is set, reset included
+	LevelStarted EventKind = iota
+	Moved
+	Undone
+	Succeeded
+	Failed
+)
+
+// Event is something happened in a game, the achievements are evaluated on them
+type Event struct {
+	Kind EventKind
+	Game string
+	// Level counts from 0
+	Level int
+	// Moves made in the level, 0 if the game doesn't count them
+	Moves      int
+	Stars      int
+	TotalStars int
+}
+
+// OnEvent registers f to be called on every event of the game
+func (b *Base) OnEvent(f func(Event)) {
+	b.onEvent = f
+}
+
+// Emit emits the event of kind, the games emit the moves and the undos themselves,
+// the others are emitted by the base
+func (b *Base) Emit(kind EventKind) {
+	if b.onEvent == nil {
+		return
+	}
+	e := Event{
+		Kind:       kind,
+		Game:       b.name,
+		Level:      b.currentLevel,
+		Stars:      b.ernedStars,
+		TotalStars: b.totalStars,
+	}
+	if b.movesFunc != nil {
+		e.Moves = b.movesFunc()
+	}
+	b.onEvent(e)
+}
@@ -3,6 +3,7 @@ package game
 import (
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
@@ -11,6 +12,8 @@ import (
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/zrcoder/rdor/pkg/dialog"
+	"github.com/zrcoder/rdor/pkg/leaderboard"
+	"github.com/zrcoder/rdor/pkg/store"
 	"github.com/zrcoder/rdor/pkg/style"
 	"github.com/zrcoder/rdor/pkg/style/color"
 )
@@ -18,6 +21,9 @@ import (
 type Game interface {
 	Name() string
 	SetParent(tea.Model)
+	SetStore(store.Store)
+	SetPlayer(name string, board leaderboard.Board)
+	OnEvent(func(Event))
 	tea.Model
 	list.Item
 }
@@ -51,6 +57,17 @@ type Base struct {
 	showSuccess    bool
 	showFailure    bool
 	showHelp       bool
+	seeded         bool
+	ownSeed        bool
+	seed           int64
+	onSuccess      func()
+	store          store.Store
+	player         string
+	board          leaderboard.Board
+	movesFunc      func() int
+	started        time.Time
+	skipRecord     bool
+	onEvent        func(Event)
 }
 
 func New(name string) *Base {
@@ -69,13 +86,56 @@ func (b *Base) SetParent(parent tea.Model) {
 	b.parent = parent
 }
 
+// SetStore sets where the game keeps its data, the default store is used if it's not set
+func (b *Base) SetStore(s store.Store) {
+	b.store = s
+}
+
+// Store is where the game keeps its data
+func (b *Base) Store() store.Store {
+	return b.store
+}
+
+// SetPlayer sets who is playing, the successes of the player are recorded in the board,
+// nothing is recorded if name is empty
+func (b *Base) SetPlayer(name string, board leaderboard.Board) {
+	b.player = name
+	b.board = board
+	b.keyMap.leaderboard.SetEnabled(name != "")
+}
+
+// Player is who is playing, empty if not set
+func (b *Base) Player() string {
+	return b.player
+}
+
+// Board is where the successes are recorded
+func (b *Base) Board() leaderboard.Board {
+	return b.board
+}
+
 func (b *Base) SetError(err error) {
 	b.Err = err
 }
 
+// SetSuccess shows the success dialog and records the success, the stars should be set before
 func (b *Base) SetSuccess(msg string) {
 	b.showSuccess = true
 	b.successMsg = msg
+	if b.skipRecord {
+		b.skipRecord = false
+	} else {
+		b.record()
+		b.Emit(Succeeded)
+	}
+	if b.onSuccess != nil {
+		b.onSuccess()
+	}
+}
+
+// OnSuccess registers f to be called whenever the game succeeds
+func (b *Base) OnSuccess(f func()) {
+	b.onSuccess = f
 }
 
 func (b *Base) SetStars(total, erned int) {
@@ -83,9 +143,37 @@ func (b *Base) SetStars(total, erned int) {
 	b.ernedStars = erned
 }
 
+// RegisterMoves registers how many moves made in the current level, recorded in the leaderboards
+func (b *Base) RegisterMoves(f func() int) {
+	b.movesFunc = f
+}
+
+// SkipRecord keeps the next success out of the leaderboards and the achievements, like one helped by a solver
+func (b *Base) SkipRecord() {
+	b.skipRecord = true
+}
+
+func (b *Base) record() {
+	if b.player == "" {
+		return
+	}
+	r := leaderboard.Record{
+		Player:  b.player,
+		Seconds: int(time.Since(b.started).Seconds()),
+		Stars:   b.ernedStars,
+	}
+	if b.movesFunc != nil {
+		r.Moves = b.movesFunc()
+	}
+	if err := b.board.Add(b.name, b.currentLevel, r); err != nil {
+		b.Err = err
+	}
+}
+
 func (b *Base) SetFailure(msg string) {
 	b.showFailure = true
 	b.failureMsg = msg
+	b.Emit(Failed)
 }
 
 func (b *Base) RegisterView(action ViewFunc) {
@@ -114,6 +202,25 @@ func (b *Base) RegisterLevels(total int, action SetLevelAction) {
 	}
 }
 
+func (b *Base) CurrentLevel() int {
+	return b.currentLevel
+}
+
+// GoToLevel sets the level i as the current one
+func (b *Base) GoToLevel(i int) {
+	b.setLevel(i)
+}
+
+// setLevel sets the level i, the time of the level counts from now on
+func (b *Base) setLevel(i int) {
+	b.currentLevel = i
+	b.started = time.Now()
+	b.skipRecord = false
+	b.totalStars, b.ernedStars = 0, 0
+	b.setLevelAction(i)
+	b.Emit(LevelStarted)
+}
+
 func (b *Base) DisabledSetKey() {
 	b.keyMap.setLevel.SetEnabled(false)
 }
@@ -134,8 +241,9 @@ func (b *Base) RegisterHelp(action ViewFunc) {
 func (b *Base) Init() tea.Cmd {
 	b.keysHelp = help.New()
 	b.keysHelp.ShowAll = true
+	b.started = time.Now()
 	if b.setLevelAction != nil {
-		b.setLevelAction(0)
+		b.setLevel(0)
 	}
 	b.newInput()
 	b.keysHelpStyle = lipgloss.NewStyle().Border(
@@ -161,14 +269,12 @@ func (b *Base) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, *b.keyMap.help):
 			b.showHelp = !b.showHelp
 		case key.Matches(msg, *b.keyMap.reset):
-			b.setLevelAction(b.currentLevel)
+			b.setLevel(b.currentLevel)
 		case key.Matches(msg, *b.keyMap.next):
-			b.currentLevel = (b.currentLevel + 1) % b.levels
-			b.setLevelAction(b.currentLevel)
+			b.setLevel((b.currentLevel + 1) % b.levels)
 			b.newInput()
 		case key.Matches(msg, *b.keyMap.previous):
-			b.currentLevel = (b.currentLevel - 1 + b.levels) % b.levels
-			b.setLevelAction(b.currentLevel)
+			b.setLevel((b.currentLevel - 1 + b.levels) % b.levels)
 			b.newInput()
 		case key.Matches(msg, *b.keyMap.setLevel):
 			b.showInput = true
@@ -196,7 +302,7 @@ func (b *Base) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (b *Base) View() string {
 	return lipgloss.NewStyle().Padding(1, 3).Render(
 		lipgloss.JoinVertical(lipgloss.Left,
-			style.Title.Render(b.name),
+			lipgloss.JoinHorizontal(lipgloss.Top, style.Title.Render(b.name), "  ", style.Help.Render(b.seedView())),
 			"",
 			lipgloss.JoinHorizontal(lipgloss.Top,
 				b.mainView(),
@@ -217,13 +323,16 @@ func (b *Base) pickLevel(s string) {
 		b.Err = fmt.Errorf("the levels must between 1 and %d", b.levels)
 		return
 	}
-	b.setLevelAction(n - 1)
+	b.setLevel(n - 1)
 }
 
 func (b *Base) mainView() string {
 	if b.showSuccess {
-		return dialog.Success(b.successMsg).
-			Stars(b.totalStars, b.ernedStars).
+		d := dialog.Success(b.successMsg)
+		if b.player != "" {
+			d.Footer(leaderboard.Key.Help().Key + " " + leaderboard.Key.Help().Desc)
+		}
+		return d.Stars(b.totalStars, b.ernedStars).
 			WhiteSpaceChars(b.name).
 			Width(b.width).Height(b.height).
 			String()
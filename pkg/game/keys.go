@@ -1,10 +1,15 @@
 package game
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/zrcoder/rdor/pkg/leaderboard"
+)
 
 type KeyMap struct {
 	help, back, quit *key.Binding
 
+	leaderboard *key.Binding
+
 	reset, next, previous, setLevel *key.Binding
 
 	groups []KeyGroup
@@ -58,6 +63,8 @@ func getCommonKeys() *KeyMap {
 		key.WithKeys("ctrl+c"),
 		key.WithHelp("ctrl+c", "quit"),
 	)
+	board := leaderboard.Key
+	board.SetEnabled(false)
 	res := &KeyMap{
 		reset:    &reset,
 		next:     &next,
@@ -66,11 +73,13 @@ func getCommonKeys() *KeyMap {
 		help:     &help,
 		back:     &back,
 		quit:     &quit,
+
+		leaderboard: &board,
 	}
 	res.groups = []KeyGroup{
 		{res.help},
 		{res.reset, res.next, res.previous, res.setLevel},
-		{res.back, res.quit},
+		{res.leaderboard, res.back, res.quit},
 	}
 	return res
 }
@@ -0,0 +1,50 @@
+package game
+
+import (
+	"fmt"
+	"hash/fnv"
+	"time"
+)
+
+// seed is the source of all the randomness in games
+var seed = time.Now().UnixNano()
+
+// SetSeed sets the seed, the same seed replays the same boards
+func SetSeed(s int64) {
+	seed = s
+}
+
+// Seed returns the seed all the games derive their randomness from
+func Seed() int64 {
+	return seed
+}
+
+// UseSeed makes the game derive its randomness from s instead of the global seed
+func (b *Base) UseSeed(s int64) {
+	b.ownSeed = true
+	b.seed = s
+}
+
+func (b *Base) gameSeed() int64 {
+	if b.ownSeed {
+		return b.seed
+	}
+	return seed
+}
+
+// LevelSeed derives a seed for the current level of the game from the seed,
+// games seed their random streams with it when a level is set,
+// so the streams of different games and levels don't affect each other
+func (b *Base) LevelSeed() int64 {
+	b.seeded = true
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d/%s/%d", b.gameSeed(), b.name, b.currentLevel)
+	return int64(h.Sum64())
+}
+
+func (b *Base) seedView() string {
+	if !b.seeded {
+		return ""
+	}
+	return fmt.Sprintf("seed %d", b.gameSeed())
+}
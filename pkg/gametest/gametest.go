@@ -0,0 +1,170 @@
+// Package gametest drives the games in tests with scripted keys,
+// and compares their views with the golden files in testdata.
+//
+// Run the tests with -update to rewrite the golden files after an intended change of the views:
+//
+//	go test ./internal/... -update
+package gametest
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/zrcoder/rdor/pkg/game"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// the window size and the seed every game is driven with
+const (
+	Width  = 100
+	Height = 40
+	Seed   = 1
+)
+
+// keyTypes are the key types by their names, like "enter" or "ctrl+h"
+var keyTypes = func() map[string]tea.KeyType {
+	res := map[string]tea.KeyType{}
+	for k := tea.KeyType(-128); k < 128; k++ {
+		if s := k.String(); s != "" {
+			res[s] = k
+		}
+	}
+	return res
+}()
+
+// Key returns the message of pressing the key named s, the names are the same as in key bindings
+func Key(s string) tea.KeyMsg {
+	msg := tea.KeyMsg{}
+	if strings.HasPrefix(s, "alt+") && len(s) > len("alt+") {
+		msg.Alt = true
+		s = strings.TrimPrefix(s, "alt+")
+	}
+	if k, ok := keyTypes[s]; ok && k != tea.KeyRunes {
+		msg.Type = k
+		if k == tea.KeySpace {
+			// the space comes with its rune from the terminal, as the inputs take it
+			msg.Runes = []rune{' '}
+		}
+		return msg
+	}
+	msg.Type = tea.KeyRunes
+	msg.Runes = []rune(s)
+	return msg
+}
+
+// Driver feeds a model with messages and records its views
+type Driver struct {
+	t     testing.TB
+	model tea.Model
+	out   strings.Builder
+}
+
+// New starts m with the fixed window size, colors and seed, and keeps the stores in a temporary directory.
+// The commands returned by the model are dropped, so the timers never fire,
+// the tests send the messages they wait for themselves.
+func New(t testing.TB, m tea.Model) *Driver {
+	t.Helper()
+	t.Setenv("RDOR_HOME", t.TempDir())
+	// the colors are kept in the golden files, some cells are only told apart by their colors
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	lipgloss.SetHasDarkBackground(true)
+	game.SetSeed(Seed)
+	m.Init()
+	d := &Driver{t: t, model: m}
+	return d.Send(tea.WindowSizeMsg{Width: Width, Height: Height})
+}
+
+// Send updates the model with the messages one by one
+func (d *Driver) Send(msgs ...tea.Msg) *Driver {
+	for _, msg := range msgs {
+		d.model, _ = d.model.Update(msg)
+	}
+	return d
+}
+
+// Press presses the keys named in keys one by one
+func (d *Driver) Press(keys ...string) *Driver {
+	for _, k := range keys {
+		d.Send(Key(k))
+	}
+	return d
+}
+
+// Type presses every character of s
+func (d *Driver) Type(s string) *Driver {
+	for _, r := range s {
+		d.Press(string(r))
+	}
+	return d
+}
+
+// Model is the current model, which changes when a game goes back to the launcher for example
+func (d *Driver) Model() tea.Model {
+	return d.model
+}
+
+// View is the current view with the trailing spaces trimmed
+func (d *Driver) View() string {
+	lines := strings.Split(d.model.View(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+// Snapshot records the current view under the title
+func (d *Driver) Snapshot(title string) *Driver {
+	d.out.WriteString("=== " + title + "\n")
+	d.out.WriteString(d.View())
+	d.out.WriteString("\n\n")
+	return d
+}
+
+// Golden compares the views recorded with testdata/<test name>.golden, or writes it with -update
+func (d *Driver) Golden() {
+	d.t.Helper()
+	path := filepath.Join("testdata", d.t.Name()+".golden")
+	got := d.out.String()
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			d.t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			d.t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		d.t.Fatalf("%v, run the test with -update to create it", err)
+	}
+	if got != string(want) {
+		d.t.Errorf("the views differ from %s, run the test with -update if it's intended\n%s", path, diff(string(want), got))
+	}
+}
+
+// diff shows the first different line of want and got
+func diff(want, got string) string {
+	wl, gl := strings.Split(want, "\n"), strings.Split(got, "\n")
+	for i := 0; i < max(len(wl), len(gl)); i++ {
+		var w, g string
+		if i < len(wl) {
+			w = wl[i]
+		}
+		if i < len(gl) {
+			g = gl[i]
+		}
+		if w != g {
+			return "line " + strconv.Itoa(i+1) + ":\nwant: " + w + "\ngot:  " + g
+		}
+	}
+	return ""
+}
@@ -10,6 +10,7 @@ var (
 	keyStyle     = lg.NewStyle().Foreground(color.Faint)
 	normalStyle  = lg.NewStyle().Padding(0, 1).Border(lg.RoundedBorder())
 	pressedStyle = normalStyle.Copy().Faint(true).Foreground(color.Faint)
+	activeStyle  = normalStyle.Copy().BorderForeground(color.Orange)
 )
 
 type Action func(key *Key)
@@ -20,6 +21,7 @@ type Key struct {
 	once    bool
 	action  Action
 	pressed bool
+	active  bool
 }
 
 func NewKey(key string) *Key {
@@ -27,7 +29,19 @@ func NewKey(key string) *Key {
 }
 
 func (k *Key) SetOnce(b bool) {
-	k.once = true
+	k.once = b
+}
+
+func (k *Key) SetPressed(b bool) {
+	k.pressed = b
+}
+
+func (k *Key) Pressed() bool {
+	return k.pressed
+}
+
+func (k *Key) SetActive(b bool) {
+	k.active = b
 }
 
 func (k *Key) SetDisply(display string) {
@@ -63,6 +77,8 @@ func (k *Key) View() string {
 	}
 	if k.pressed {
 		display = pressedStyle.Render(display)
+	} else if k.active {
+		display = activeStyle.Render(display)
 	} else {
 		display = normalStyle.Render(display)
 	}
@@ -98,6 +114,12 @@ func (kl KeysLine) SetDisplay(i int, display string) {
 	kl[i].SetDisply(display)
 }
 
+func (kl KeysLine) SetActive(i int) {
+	for j := range kl {
+		kl[j].SetActive(j == i)
+	}
+}
+
 func (kl KeysLine) SetAction(action Action) {
 	for i := range kl {
 		kl[i].SetAction(action)
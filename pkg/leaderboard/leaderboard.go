@@ -0,0 +1,170 @@
+// Package leaderboard keeps the best results of every level of the games in a local file,
+// shared by all the players on the machine.
+package leaderboard
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/zrcoder/rdor/pkg/store"
+)
+
+const (
+	storeKey = "leaderboard"
+	// Top is the number of records shown and kept for every order of a level
+	Top = 5
+)
+
+// Record is a result of a level
+type Record struct {
+	Player  string `json:"player"`
+	Moves   int    `json:"moves"`
+	Seconds int    `json:"seconds"`
+	Stars   int    `json:"stars"`
+}
+
+// Order is how the records of a level are ranked
+type Order int
+
+const (
+	FewestMoves Order = iota
+	Fastest
+	MostStars
+)
+
+var orderNames = []string{"fewest moves", "fastest", "most stars"}
+
+func (o Order) String() string {
+	return orderNames[o]
+}
+
+// less reports whether a ranks before b, the ties are broken by the other orders
+func (o Order) less(a, b Record) bool {
+	switch o {
+	case FewestMoves:
+		if a.Moves != b.Moves {
+			return a.Moves < b.Moves
+		}
+		return a.Seconds < b.Seconds
+	case Fastest:
+		if a.Seconds != b.Seconds {
+			return a.Seconds < b.Seconds
+		}
+		return a.Moves < b.Moves
+	default:
+		if a.Stars != b.Stars {
+			return a.Stars > b.Stars
+		}
+		return a.Seconds < b.Seconds
+	}
+}
+
+// ranked tells whether the records of the order make sense, the games without moves or stars record zeros
+func (o Order) ranked(r Record) bool {
+	switch o {
+	case FewestMoves:
+		return r.Moves > 0
+	case MostStars:
+		return r.Stars > 0
+	}
+	return true
+}
+
+// Board is the leaderboards of all the levels kept in a store
+type Board struct {
+	Store store.Store
+}
+
+// records of all the levels by the game and the level counting from 1, like "Hanoi/1"
+type records map[string][]Record
+
+// the sessions hosted over ssh may record at the same time
+var mu sync.Mutex
+
+func levelKey(game string, level int) string {
+	return fmt.Sprintf("%s/%d", game, level+1)
+}
+
+// Add records r of the level counting from 0 of the game,
+// only the records in the top of any order are kept
+func (b Board) Add(game string, level int, r Record) error {
+	mu.Lock()
+	defer mu.Unlock()
+	all := records{}
+	if err := b.Store.Load(storeKey, &all); err != nil {
+		return err
+	}
+	k := levelKey(game, level)
+	all[k] = trim(append(all[k], r))
+	return b.Store.Save(storeKey, all)
+}
+
+// Records returns the records of the level counting from 0 of the game
+func (b Board) Records(game string, level int) ([]Record, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	all := records{}
+	if err := b.Store.Load(storeKey, &all); err != nil {
+		return nil, err
+	}
+	return all[levelKey(game, level)], nil
+}
+
+// Levels returns the levels counting from 0 of the game that have records, in order
+func (b Board) Levels(game string) ([]int, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	all := records{}
+	if err := b.Store.Load(storeKey, &all); err != nil {
+		return nil, err
+	}
+	var res []int
+	for k := range all {
+		s, ok := strings.CutPrefix(k, game+"/")
+		if !ok {
+			continue
+		}
+		if level, err := strconv.Atoi(s); err == nil {
+			res = append(res, level-1)
+		}
+	}
+	sort.Ints(res)
+	return res, nil
+}
+
+// Rank returns the best records in the order, at most Top of them
+func Rank(rs []Record, o Order) []Record {
+	res := make([]Record, 0, len(rs))
+	for _, r := range rs {
+		if o.ranked(r) {
+			res = append(res, r)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool { return o.less(res[i], res[j]) })
+	return res[:min(len(res), Top)]
+}
+
+// trim drops the records out of the top of every order
+func trim(rs []Record) []Record {
+	keep := make([]bool, len(rs))
+	for _, o := range []Order{FewestMoves, Fastest, MostStars} {
+		idx := make([]int, len(rs))
+		for i := range idx {
+			idx[i] = i
+		}
+		sort.SliceStable(idx, func(i, j int) bool { return o.less(rs[idx[i]], rs[idx[j]]) })
+		for _, i := range idx[:min(len(idx), Top)] {
+			keep[i] = true
+		}
+	}
+	res := rs[:0]
+	for i, r := range rs {
+		if keep[i] {
+			res = append(res, r)
+		}
+	}
+	return res
+}
@@ -0,0 +1,123 @@
+package leaderboard
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zrcoder/rdor/pkg/style"
+)
+
+// Key shows the leaderboard of the current level in games
+var Key = key.NewBinding(key.WithKeys("ctrl+l"), key.WithHelp("ctrl+l", "leaderboard"))
+
+var (
+	prevKey = key.NewBinding(key.WithKeys("left"), key.WithHelp("←", "previous level"))
+	nextKey = key.NewBinding(key.WithKeys("right"), key.WithHelp("→", "next level"))
+	backKey = key.NewBinding(key.WithKeys("esc", "ctrl+h"), key.WithHelp("esc", "back"))
+	quitKey = key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "quit"))
+
+	columnStyle = lipgloss.NewStyle().Width(28)
+)
+
+// View is the screen of the leaderboards of a game,
+// it switches among the levels with records, and goes back to the parent with esc
+type View struct {
+	board  Board
+	game   string
+	parent tea.Model
+	levels []int
+	index  int // the current level in levels
+	err    error
+	help   help.Model
+}
+
+// NewView shows the leaderboard of the level counting from 0 of the game first
+func NewView(b Board, game string, level int, parent tea.Model) *View {
+	return &View{board: b, game: game, parent: parent, levels: []int{level}, help: help.New()}
+}
+
+func (v *View) Init() tea.Cmd {
+	level := v.levels[v.index]
+	levels, err := v.board.Levels(v.game)
+	if err != nil {
+		v.err = err
+		return nil
+	}
+	if !slices.Contains(levels, level) {
+		levels = append(levels, level)
+		slices.Sort(levels)
+	}
+	v.levels = levels
+	v.index = slices.Index(levels, level)
+	return nil
+}
+
+func (v *View) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, quitKey):
+			return v, tea.Quit
+		case key.Matches(msg, backKey):
+			return v.parent, nil
+		case key.Matches(msg, prevKey):
+			v.index = (v.index - 1 + len(v.levels)) % len(v.levels)
+		case key.Matches(msg, nextKey):
+			v.index = (v.index + 1) % len(v.levels)
+		}
+	}
+	return v, nil
+}
+
+func (v *View) View() string {
+	level := v.levels[v.index]
+	views := []string{
+		style.Title.Render(v.game + " leaderboard"),
+		"",
+		fmt.Sprintf("level %d", level+1),
+		"",
+	}
+	rs, err := v.board.Records(v.game, level)
+	if v.err != nil {
+		err = v.err
+	}
+	switch {
+	case err != nil:
+		views = append(views, style.Error.Render(err.Error()))
+	case len(rs) == 0:
+		views = append(views, style.Help.Render("no records yet"))
+	default:
+		columns := make([]string, 0, 3)
+		for _, o := range []Order{FewestMoves, Fastest, MostStars} {
+			columns = append(columns, columnStyle.Render(column(o, Rank(rs, o))))
+		}
+		views = append(views, lipgloss.JoinHorizontal(lipgloss.Top, columns...))
+	}
+	views = append(views, "", v.help.ShortHelpView([]key.Binding{prevKey, nextKey, backKey, quitKey}))
+	return lipgloss.NewStyle().Padding(1, 3).Render(lipgloss.JoinVertical(lipgloss.Left, views...))
+}
+
+func column(o Order, rs []Record) string {
+	lines := []string{style.Success.Render(o.String()), ""}
+	if len(rs) == 0 {
+		lines = append(lines, style.Help.Render("-"))
+	}
+	for i, r := range rs {
+		var score string
+		switch o {
+		case FewestMoves:
+			score = fmt.Sprintf("%d", r.Moves)
+		case Fastest:
+			score = (time.Duration(r.Seconds) * time.Second).String()
+		default:
+			score = strings.Repeat("★", r.Stars)
+		}
+		lines = append(lines, fmt.Sprintf("%d. %-12s %s", i+1, r.Player, score))
+	}
+	return strings.Join(lines, "\n")
+}
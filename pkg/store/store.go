@@ -0,0 +1,69 @@
+// Package store keeps the local data of the games as json files,
+// in $RDOR_HOME or the rdor directory of the user config directory.
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Store keeps the data in a directory, the zero value is the default directory,
+// every player has a store of its own when rdor is hosted over ssh
+type Store struct {
+	// Dir is the directory of the files, empty for the default one
+	Dir string
+}
+
+// Home is the default directory
+func Home() (string, error) {
+	if home := os.Getenv("RDOR_HOME"); home != "" {
+		return home, nil
+	}
+	cfg, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cfg, "rdor"), nil
+}
+
+func (s Store) dir() (string, error) {
+	if s.Dir != "" {
+		return s.Dir, nil
+	}
+	return Home()
+}
+
+// Load reads the data saved with name into v, v is untouched if nothing saved yet
+func (s Store) Load(name string, v any) error {
+	d, err := s.dir()
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(filepath.Join(d, name+".json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// Save writes v with name
+func (s Store) Save(name string, v any) error {
+	d, err := s.dir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(d, 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(d, name+".json"), data, 0o600)
+}